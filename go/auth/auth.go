@@ -0,0 +1,101 @@
+// Package auth gates POST /create (and anything else that needs a caller
+// identity) behind pluggable bearer-token connectors. Each connector knows
+// how to turn a raw token into an Identity; additional providers (Bitbucket,
+// Google, ...) can be added by implementing Connector without touching the
+// handlers in package main.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Identity is the caller resolved from a bearer token.
+type Identity struct {
+	// Sub is a stable, provider-scoped subject id used to tag owned
+	// resources (URLRecord.OwnerSub).
+	Sub string
+	// Login is a human-readable identifier (GitHub username, email, ...)
+	// for display purposes only.
+	Login    string
+	Provider string
+}
+
+// Connector authenticates a bearer token against one identity provider.
+type Connector interface {
+	Name() string
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+// ErrNoToken is returned when the caller supplied no bearer token at all.
+var ErrNoToken = errors.New("auth: no bearer token supplied")
+
+// ErrUnauthenticated is returned when every configured connector rejected
+// the token.
+var ErrUnauthenticated = errors.New("auth: token rejected by all configured connectors")
+
+// Router tries each configured Connector in order and returns the first
+// successful Identity.
+type Router struct {
+	connectors []Connector
+}
+
+// NewRouterFromEnv builds a Router from the AUTH_CONNECTORS env var, a
+// comma-separated list such as "github,oidc". Unknown names are ignored.
+func NewRouterFromEnv(httpClient *http.Client) *Router {
+	names := strings.Split(os.Getenv("AUTH_CONNECTORS"), ",")
+	r := &Router{}
+
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case "github":
+			r.connectors = append(r.connectors, NewGitHubConnectorFromEnv(httpClient))
+		case "oidc":
+			r.connectors = append(r.connectors, NewOIDCConnectorFromEnv(httpClient))
+		}
+	}
+
+	return r
+}
+
+// Enabled reports whether at least one connector is configured.
+func (r *Router) Enabled() bool {
+	return r != nil && len(r.connectors) > 0
+}
+
+// Authenticate tries every configured connector in order, returning the
+// first Identity resolved successfully.
+func (r *Router) Authenticate(ctx context.Context, token string) (Identity, error) {
+	if token == "" {
+		return Identity{}, ErrNoToken
+	}
+
+	var errs []string
+	for _, c := range r.connectors {
+		identity, err := c.Authenticate(ctx, token)
+		if err == nil {
+			identity.Provider = c.Name()
+			return identity, nil
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", c.Name(), err))
+	}
+
+	if len(errs) == 0 {
+		return Identity{}, ErrUnauthenticated
+	}
+	return Identity{}, fmt.Errorf("%w (%s)", ErrUnauthenticated, strings.Join(errs, "; "))
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>"
+// header value. It returns "" if the header is absent or malformed.
+func BearerToken(authorizationHeader string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(authorizationHeader, prefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authorizationHeader, prefix))
+}
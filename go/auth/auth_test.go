@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBearerToken(t *testing.T) {
+	cases := []struct {
+		desc   string
+		header string
+		want   string
+	}{
+		{"well formed", "Bearer abc123", "abc123"},
+		{"missing prefix", "abc123", ""},
+		{"empty header", "", ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			if got := BearerToken(c.header); got != c.want {
+				t.Errorf("BearerToken(%q) = %q, want %q", c.header, got, c.want)
+			}
+		})
+	}
+}
+
+func TestGitHubConnectorAuthenticate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/user":
+			json.NewEncoder(w).Encode(githubUser{Login: "octocat", ID: 1})
+		case "/user/orgs":
+			json.NewEncoder(w).Encode([]githubOrg{{Login: "vibtellect"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("valid token, no org restriction", func(t *testing.T) {
+		c := &GitHubConnector{APIBase: server.URL, HTTPClient: server.Client()}
+		id, err := c.Authenticate(context.Background(), "valid-token")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id.Sub != "github:1" || id.Login != "octocat" {
+			t.Errorf("unexpected identity: %+v", id)
+		}
+	})
+
+	t.Run("org member allowed", func(t *testing.T) {
+		c := &GitHubConnector{APIBase: server.URL, HTTPClient: server.Client(), AllowedOrg: "vibtellect"}
+		if _, err := c.Authenticate(context.Background(), "valid-token"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("non-member rejected", func(t *testing.T) {
+		c := &GitHubConnector{APIBase: server.URL, HTTPClient: server.Client(), AllowedOrg: "someone-else"}
+		if _, err := c.Authenticate(context.Background(), "valid-token"); err == nil {
+			t.Fatal("expected error for non-member, got nil")
+		}
+	})
+
+	t.Run("invalid token rejected", func(t *testing.T) {
+		c := &GitHubConnector{APIBase: server.URL, HTTPClient: server.Client()}
+		if _, err := c.Authenticate(context.Background(), "bad-token"); err == nil {
+			t.Fatal("expected error for invalid token, got nil")
+		}
+	})
+}
+
+func TestOIDCConnectorAuthenticate(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(oidcDiscovery{JWKSURI: server.URL + "/jwks"})
+		case "/jwks":
+			json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{{
+				Kid: "test-key",
+				Kty: "RSA",
+				N:   base64.RawURLEncoding.EncodeToString(priv.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(priv.PublicKey.E)).Bytes()),
+			}}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	connector := &OIDCConnector{
+		Issuer:     server.URL,
+		Audience:   "test-audience",
+		HTTPClient: server.Client(),
+		keyTTL:     time.Minute,
+	}
+
+	t.Run("valid token accepted", func(t *testing.T) {
+		token := signTestToken(t, priv, "test-key", jwtClaims{
+			Sub: "user-1", Issuer: server.URL, Audience: rawJSONString("test-audience"),
+			Exp: time.Now().Add(time.Hour).Unix(),
+		})
+		id, err := connector.Authenticate(context.Background(), token)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id.Sub != "oidc:user-1" {
+			t.Errorf("unexpected identity: %+v", id)
+		}
+	})
+
+	t.Run("expired token rejected", func(t *testing.T) {
+		token := signTestToken(t, priv, "test-key", jwtClaims{
+			Sub: "user-1", Issuer: server.URL, Audience: rawJSONString("test-audience"),
+			Exp: time.Now().Add(-time.Hour).Unix(),
+		})
+		if _, err := connector.Authenticate(context.Background(), token); err == nil {
+			t.Fatal("expected error for expired token, got nil")
+		}
+	})
+
+	t.Run("wrong audience rejected", func(t *testing.T) {
+		token := signTestToken(t, priv, "test-key", jwtClaims{
+			Sub: "user-1", Issuer: server.URL, Audience: rawJSONString("other-audience"),
+			Exp: time.Now().Add(time.Hour).Unix(),
+		})
+		if _, err := connector.Authenticate(context.Background(), token); err == nil {
+			t.Fatal("expected error for wrong audience, got nil")
+		}
+	})
+
+	t.Run("malformed token rejected", func(t *testing.T) {
+		if _, err := connector.Authenticate(context.Background(), "not-a-jwt"); err == nil {
+			t.Fatal("expected error for malformed token, got nil")
+		}
+	})
+}
+
+func TestRouterAuthenticate(t *testing.T) {
+	githubServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user" {
+			json.NewEncoder(w).Encode(githubUser{Login: "octocat", ID: 42})
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer githubServer.Close()
+
+	router := &Router{connectors: []Connector{
+		&GitHubConnector{APIBase: githubServer.URL, HTTPClient: githubServer.Client()},
+	}}
+
+	t.Run("no token", func(t *testing.T) {
+		if _, err := router.Authenticate(context.Background(), ""); err != ErrNoToken {
+			t.Errorf("expected ErrNoToken, got %v", err)
+		}
+	})
+
+	t.Run("delegates to connector", func(t *testing.T) {
+		id, err := router.Authenticate(context.Background(), "any-token")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if id.Provider != "github" {
+			t.Errorf("expected provider github, got %s", id.Provider)
+		}
+	})
+}
+
+func rawJSONString(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
+
+func signTestToken(t *testing.T, priv *rsa.PrivateKey, kid string, claims jwtClaims) string {
+	t.Helper()
+
+	header := jwtHeader{Alg: "RS256", Kid: kid}
+	headerJSON, _ := json.Marshal(header)
+	claimsJSON, _ := json.Marshal(claims)
+
+	signedPart := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signedPart))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	return signedPart + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const defaultGitHubAPIBase = "https://api.github.com"
+
+// GitHubConnector authenticates a token by calling the GitHub API as that
+// token. It optionally restricts access to members of AllowedOrg.
+type GitHubConnector struct {
+	ClientID   string
+	AllowedOrg string
+	APIBase    string
+	HTTPClient *http.Client
+}
+
+// NewGitHubConnectorFromEnv builds a GitHubConnector from GITHUB_CLIENT_ID
+// and GITHUB_ALLOWED_ORG.
+func NewGitHubConnectorFromEnv(httpClient *http.Client) *GitHubConnector {
+	return &GitHubConnector{
+		ClientID:   os.Getenv("GITHUB_CLIENT_ID"),
+		AllowedOrg: os.Getenv("GITHUB_ALLOWED_ORG"),
+		APIBase:    defaultGitHubAPIBase,
+		HTTPClient: httpClient,
+	}
+}
+
+func (c *GitHubConnector) Name() string { return "github" }
+
+type githubUser struct {
+	Login string `json:"login"`
+	ID    int64  `json:"id"`
+}
+
+type githubOrg struct {
+	Login string `json:"login"`
+}
+
+// Authenticate verifies token via GET /user and, if AllowedOrg is set,
+// confirms membership via GET /user/orgs.
+func (c *GitHubConnector) Authenticate(ctx context.Context, token string) (Identity, error) {
+	user, err := c.fetchUser(ctx, token)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if c.AllowedOrg != "" {
+		member, err := c.isOrgMember(ctx, token, c.AllowedOrg)
+		if err != nil {
+			return Identity{}, err
+		}
+		if !member {
+			return Identity{}, fmt.Errorf("github: user %s is not a member of org %s", user.Login, c.AllowedOrg)
+		}
+	}
+
+	return Identity{
+		Sub:   fmt.Sprintf("github:%d", user.ID),
+		Login: user.Login,
+	}, nil
+}
+
+func (c *GitHubConnector) fetchUser(ctx context.Context, token string) (githubUser, error) {
+	var user githubUser
+	if err := c.getJSON(ctx, token, "/user", &user); err != nil {
+		return githubUser{}, fmt.Errorf("github: fetch user: %w", err)
+	}
+	return user, nil
+}
+
+func (c *GitHubConnector) isOrgMember(ctx context.Context, token, org string) (bool, error) {
+	var orgs []githubOrg
+	if err := c.getJSON(ctx, token, "/user/orgs", &orgs); err != nil {
+		return false, fmt.Errorf("github: fetch orgs: %w", err)
+	}
+	for _, o := range orgs {
+		if o.Login == org {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *GitHubConnector) getJSON(ctx context.Context, token, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.APIBase+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, path)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
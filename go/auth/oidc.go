@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OIDCConnector validates RS256 ID tokens against a cached JWKS fetched
+// from the issuer's discovery document.
+type OIDCConnector struct {
+	Issuer     string
+	Audience   string
+	HTTPClient *http.Client
+
+	mu     sync.Mutex
+	keys   map[string]*rsa.PublicKey
+	keysAt time.Time
+	keyTTL time.Duration
+}
+
+// NewOIDCConnectorFromEnv builds an OIDCConnector from OIDC_ISSUER and
+// OIDC_AUDIENCE.
+func NewOIDCConnectorFromEnv(httpClient *http.Client) *OIDCConnector {
+	return &OIDCConnector{
+		Issuer:     os.Getenv("OIDC_ISSUER"),
+		Audience:   os.Getenv("OIDC_AUDIENCE"),
+		HTTPClient: httpClient,
+		keyTTL:     10 * time.Minute,
+	}
+}
+
+func (c *OIDCConnector) Name() string { return "oidc" }
+
+type oidcDiscovery struct {
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+// Authenticate validates token as an RS256 ID token issued by c.Issuer.
+func (c *OIDCConnector) Authenticate(ctx context.Context, token string) (Identity, error) {
+	header, claims, signedPart, signature, err := splitJWT(token)
+	if err != nil {
+		return Identity{}, fmt.Errorf("oidc: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return Identity{}, fmt.Errorf("oidc: unsupported alg %q", header.Alg)
+	}
+
+	key, err := c.publicKey(ctx, header.Kid)
+	if err != nil {
+		return Identity{}, fmt.Errorf("oidc: %w", err)
+	}
+
+	digest := sha256.Sum256([]byte(signedPart))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return Identity{}, fmt.Errorf("oidc: signature verification failed: %w", err)
+	}
+
+	if claims.Issuer != c.Issuer {
+		return Identity{}, fmt.Errorf("oidc: unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.hasAudience(c.Audience) {
+		return Identity{}, fmt.Errorf("oidc: token not issued for audience %q", c.Audience)
+	}
+	if time.Now().Unix() > claims.Exp {
+		return Identity{}, fmt.Errorf("oidc: token expired")
+	}
+
+	return Identity{Sub: "oidc:" + claims.Sub, Login: claims.Email}, nil
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Sub      string          `json:"sub"`
+	Email    string          `json:"email"`
+	Issuer   string          `json:"iss"`
+	Audience json.RawMessage `json:"aud"`
+	Exp      int64           `json:"exp"`
+}
+
+// hasAudience handles both the single-string and string-array encodings of
+// the "aud" claim permitted by the JWT spec.
+func (c jwtClaims) hasAudience(want string) bool {
+	var single string
+	if err := json.Unmarshal(c.Audience, &single); err == nil {
+		return single == want
+	}
+	var many []string
+	if err := json.Unmarshal(c.Audience, &many); err == nil {
+		for _, a := range many {
+			if a == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func splitJWT(token string) (jwtHeader, jwtClaims, string, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("malformed JWT: expected 3 parts, got %d", len(parts))
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("decode header: %w", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("parse header: %w", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("decode claims: %w", err)
+	}
+	var claims jwtClaims
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("parse claims: %w", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return jwtHeader{}, jwtClaims{}, "", nil, fmt.Errorf("decode signature: %w", err)
+	}
+
+	return header, claims, parts[0] + "." + parts[1], signature, nil
+}
+
+// publicKey returns the cached RSA public key for kid, refreshing the JWKS
+// from the issuer if the cache is stale or the key is unknown.
+func (c *OIDCConnector) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.keys[kid]; ok && time.Since(c.keysAt) < c.keyTTL {
+		return key, nil
+	}
+
+	keys, err := c.fetchJWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.keys = keys
+	c.keysAt = time.Now()
+
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("no key found for kid %q", kid)
+	}
+	return key, nil
+}
+
+func (c *OIDCConnector) fetchJWKS(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var discovery oidcDiscovery
+	if err := c.getJSON(ctx, strings.TrimSuffix(c.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("fetch discovery document: %w", err)
+	}
+
+	var set jwkSet
+	if err := c.getJSON(ctx, discovery.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("fetch JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pubKey, err := jwkToRSAPublicKey(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pubKey
+	}
+	return keys, nil
+}
+
+func jwkToRSAPublicKey(k jwk) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func (c *OIDCConnector) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
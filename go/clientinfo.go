@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/vibtellect/url-shortener-aws/go/useragent"
+)
+
+// headerValue looks up an HTTP header case-insensitively. API Gateway v2
+// normally lowercases header names, but this guards against callers or test
+// fixtures that don't.
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+// formatClientHeader renders a classification as the X-Shortener-Client
+// response header value.
+func formatClientHeader(c useragent.Info) string {
+	return fmt.Sprintf("platform=%s;os=%s;browser=%s;bot=%t", c.Platform, c.OS, c.Browser, c.IsBot)
+}
+
+// publishClientMetrics emits one CloudWatch datum per classification
+// dimension so Platform/OS/Browser/IsBot can each be sliced independently.
+// CloudWatch treats a datum's full dimension set as the metric's identity,
+// so folding all four onto one datum would only let you query the exact
+// (platform, os, browser, bot) tuple together - not "by Platform" alone.
+func publishClientMetrics(ctx context.Context, c useragent.Info) {
+	if cloudwatchClient == nil {
+		return
+	}
+
+	dimensions := []types.Dimension{
+		{Name: aws.String("Platform"), Value: aws.String(c.Platform)},
+		{Name: aws.String("OS"), Value: aws.String(c.OS)},
+		{Name: aws.String("Browser"), Value: aws.String(c.Browser)},
+		{Name: aws.String("IsBot"), Value: aws.String(fmt.Sprintf("%t", c.IsBot))},
+	}
+
+	metricData := make([]types.MetricDatum, len(dimensions))
+	for i, dim := range dimensions {
+		metricData[i] = types.MetricDatum{
+			MetricName: aws.String("ClientRequests"),
+			Value:      aws.Float64(1),
+			Unit:       types.StandardUnitCount,
+			Dimensions: []types.Dimension{dim},
+		}
+	}
+
+	_, err := cloudwatchClient.PutMetricData(ctx, &cloudwatch.PutMetricDataInput{
+		Namespace:  aws.String("UrlShortener/Demo"),
+		MetricData: metricData,
+	})
+	if err != nil {
+		log.Printf("Failed to publish client metrics: %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vibtellect/url-shortener-aws/go/useragent"
+)
+
+func TestHeaderValue(t *testing.T) {
+	headers := map[string]string{"user-agent": "curl/8.4.0"}
+
+	if got := headerValue(headers, "User-Agent"); got != "curl/8.4.0" {
+		t.Errorf("headerValue case-insensitive lookup = %q, want %q", got, "curl/8.4.0")
+	}
+	if got := headerValue(headers, "X-Missing"); got != "" {
+		t.Errorf("headerValue for missing header = %q, want empty", got)
+	}
+}
+
+func TestFormatClientHeader(t *testing.T) {
+	c := useragent.Info{Platform: "Desktop", OS: "Windows", Browser: "Chrome", IsBot: false}
+	got := formatClientHeader(c)
+
+	for _, want := range []string{"platform=Desktop", "os=Windows", "browser=Chrome", "bot=false"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("formatClientHeader(%+v) = %q, want it to contain %q", c, got, want)
+		}
+	}
+}
@@ -2,13 +2,13 @@ package main
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -20,6 +20,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/vibtellect/url-shortener-aws/go/auth"
+	"github.com/vibtellect/url-shortener-aws/go/ratelimit"
+	"github.com/vibtellect/url-shortener-aws/go/useragent"
 )
 
 type URLRecord struct {
@@ -28,16 +32,26 @@ type URLRecord struct {
 	ExpiresAt   int64  `dynamodbav:"expires_at"`
 	CreatedAt   string `dynamodbav:"created_at"`
 	ClickCount  int64  `dynamodbav:"click_count,omitempty"`
+	OwnerSub    string `dynamodbav:"owner_sub,omitempty"`
+	Title       string `dynamodbav:"title,omitempty"`
+	Description string `dynamodbav:"description,omitempty"`
 }
 
 var (
 	dynamoClient     *dynamodb.Client
 	cloudwatchClient *cloudwatch.Client
 	tableName        string
+	ownerIndexName   string
 	baseURL          string
+	authRouter       *auth.Router
+	metricsReader    MetricsReader
 )
 
+const defaultOwnerIndexName = "owner_sub-index"
+
 func init() {
+	authRouter = auth.NewRouterFromEnv(http.DefaultClient)
+
 	// Skip AWS initialization in test mode
 	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") == "" && os.Getenv("TEST_MODE") == "" {
 		// Running locally or in tests, don't initialize AWS clients
@@ -45,6 +59,10 @@ func init() {
 		if tableName == "" {
 			tableName = "test-table" // Default for tests
 		}
+		ownerIndexName = os.Getenv("OWNER_INDEX_NAME")
+		if ownerIndexName == "" {
+			ownerIndexName = defaultOwnerIndexName
+		}
 		baseURL = os.Getenv("BASE_URL")
 		if baseURL == "" {
 			baseURL = "https://url-shortener.vibtellect.de"
@@ -59,7 +77,12 @@ func init() {
 
 	dynamoClient = dynamodb.NewFromConfig(cfg)
 	cloudwatchClient = cloudwatch.NewFromConfig(cfg)
+	metricsReader = &cloudWatchMetricsReader{client: cloudwatchClient, namespace: metricsNamespace}
 	tableName = os.Getenv("DYNAMODB_TABLE")
+	ownerIndexName = os.Getenv("OWNER_INDEX_NAME")
+	if ownerIndexName == "" {
+		ownerIndexName = defaultOwnerIndexName
+	}
 	baseURL = os.Getenv("BASE_URL")
 
 	if tableName == "" {
@@ -68,6 +91,18 @@ func init() {
 	if baseURL == "" {
 		baseURL = "https://url-shortener.vibtellect.de"
 	}
+
+	rateLimiter = &ratelimit.Limiter{Client: ratelimit.Adapt(dynamoClient), TableName: tableName}
+
+	if bucket, key := os.Getenv("BLOCKLIST_S3_BUCKET"), os.Getenv("BLOCKLIST_S3_KEY"); bucket != "" && key != "" {
+		s3Client := s3.NewFromConfig(cfg)
+		loaded, err := ratelimit.LoadBlocklistFromS3(context.TODO(), s3Client, bucket, key)
+		if err != nil {
+			log.Printf("Failed to load URL blocklist, continuing without it: %v", err)
+		} else {
+			blocklist = loaded
+		}
+	}
 }
 
 func handleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
@@ -76,13 +111,33 @@ func handleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest)
 		"Content-Type": "application/json",
 	}
 
-	// Handle POST /create
+	// Handle POST /create. Gate by IP here, before authentication runs, so a
+	// caller can't dodge the limiter for free by sending garbage bearer
+	// tokens and forcing an Authenticate round-trip on every request. A
+	// second, owner-scoped budget is checked inside handleCreate once
+	// identity is resolved.
 	if request.RequestContext.HTTP.Method == "POST" && request.RawPath == "/create" {
+		if limited := rateLimitGate(ctx, request, headers, "create", rateLimitCreatePerMin()); limited != nil {
+			return *limited, nil
+		}
 		return handleCreate(ctx, request, headers)
 	}
 
+	// Handle GET /s/{shortCode}/qr
+	if request.RequestContext.HTTP.Method == "GET" && request.PathParameters["shortCode"] != "" && strings.HasSuffix(request.RawPath, "/qr") {
+		return handleQR(ctx, request, headers)
+	}
+
+	// Handle GET /s/{shortCode}/preview
+	if request.RequestContext.HTTP.Method == "GET" && request.PathParameters["shortCode"] != "" && strings.HasSuffix(request.RawPath, "/preview") {
+		return handlePreview(ctx, request, headers)
+	}
+
 	// Handle GET /s/{shortCode} - konsistent mit API Gateway Route
 	if request.RequestContext.HTTP.Method == "GET" && request.PathParameters["shortCode"] != "" {
+		if limited := rateLimitGate(ctx, request, headers, "redirect", rateLimitRedirectPerMin()); limited != nil {
+			return *limited, nil
+		}
 		return handleRedirect(ctx, request, headers)
 	}
 
@@ -91,6 +146,11 @@ func handleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest)
 		return handleMetrics(ctx, request, headers)
 	}
 
+	// Handle GET /mine
+	if request.RequestContext.HTTP.Method == "GET" && request.RawPath == "/mine" {
+		return handleMine(ctx, request, headers)
+	}
+
 	return events.APIGatewayV2HTTPResponse{
 		StatusCode: 404,
 		Headers:    headers,
@@ -99,6 +159,24 @@ func handleRequest(ctx context.Context, request events.APIGatewayV2HTTPRequest)
 }
 
 func handleCreate(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
+	var identity auth.Identity
+	if authRouter.Enabled() {
+		token := auth.BearerToken(headerValue(request.Headers, "Authorization"))
+		var err error
+		identity, err = authRouter.Authenticate(ctx, token)
+		if err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 401,
+				Headers:    headers,
+				Body:       `{"error": "Unauthorized"}`,
+			}, nil
+		}
+	}
+
+	if limited := rateLimitOwnerGate(ctx, headers, "create", rateLimitCreatePerMin(), identity.Sub); limited != nil {
+		return *limited, nil
+	}
+
 	// Parse JSON request body - vereinfacht, da Frontend nur JSON sendet
 	var requestData map[string]string
 	if err := json.Unmarshal([]byte(request.Body), &requestData); err != nil {
@@ -128,44 +206,90 @@ func handleCreate(ctx context.Context, request events.APIGatewayV2HTTPRequest, h
 		}, nil
 	}
 
-	// Generate short code
-	hash := sha256.Sum256([]byte(rawURL))
-	shortCode := hex.EncodeToString(hash[:])[:8]
+	if blockedHost(parsedURL.Hostname()) {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       `{"error": "URL host is not allowed"}`,
+		}, nil
+	}
 
 	// Create URL record with TTL (expires in 1 week)
 	expiresAt := time.Now().Add(7 * 24 * time.Hour).Unix()
 	record := URLRecord{
-		ShortCode:   shortCode,
 		OriginalURL: rawURL,
 		ExpiresAt:   expiresAt,
 		CreatedAt:   time.Now().Format(time.RFC3339),
 		ClickCount:  0,
+		OwnerSub:    identity.Sub,
 	}
 
-	// Convert to DynamoDB item
-	item, err := attributevalue.MarshalMap(record)
-	if err != nil {
-		log.Printf("Failed to marshal record: %v", err)
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: 500,
-			Headers:    headers,
-			Body:       `{"error": "Internal server error"}`,
-		}, nil
+	// A caller-supplied "custom" alias in the request body takes priority over
+	// the configured strategy.
+	var shortCode string
+	if custom := requestData["custom"]; custom != "" {
+		if !validateCustomAlias(custom) {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 400,
+				Headers:    headers,
+				Body:       `{"error": "Invalid custom alias: must match ^[A-Za-z0-9_-]{3,32}$"}`,
+			}, nil
+		}
+		ok, err := reserveShortCode(ctx, dynamoClient, custom, record)
+		if err != nil {
+			log.Printf("Failed to reserve custom alias: %v", err)
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 500,
+				Headers:    headers,
+				Body:       `{"error": "Internal server error"}`,
+			}, nil
+		}
+		if !ok {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 409,
+				Headers:    headers,
+				Body:       `{"error": "Custom alias already in use"}`,
+			}, nil
+		}
+		shortCode = custom
+	} else {
+		var err error
+		shortCode, err = allocateShortCode(ctx, dynamoClient, shortCodeStrategyFromEnv(), record)
+		if err != nil {
+			log.Printf("Failed to allocate short code: %v", err)
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 500,
+				Headers:    headers,
+				Body:       `{"error": "Internal server error"}`,
+			}, nil
+		}
 	}
 
-	// Put item in DynamoDB
-	_, err = dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(tableName),
-		Item:      item,
-	})
-	if err != nil {
-		log.Printf("Failed to put item in DynamoDB: %v", err)
-		return events.APIGatewayV2HTTPResponse{
-			StatusCode: 500,
-			Headers:    headers,
-			Body:       `{"error": "Internal server error"}`,
-		}, nil
+	if err := incrementStat(ctx, statsActiveKey, 1); err != nil {
+		log.Printf("Failed to increment active_urls counter: %v", err)
+	}
+	if err := incrementStat(ctx, statsTotalKey, 1); err != nil {
+		log.Printf("Failed to increment urls_created counter: %v", err)
 	}
+	go func() {
+		ctx := context.Background()
+		publishCustomMetric(ctx, "UrlsCreated", 1)
+	}()
+
+	// Fetching the destination's title/description is best-effort and can
+	// be slow (or target a host that never responds), so it runs off the
+	// request's hot path; /preview simply sees an empty title/description
+	// until this finishes.
+	go func() {
+		ctx := context.Background()
+		title, description := fetchPageMeta(rawURL)
+		if title == "" && description == "" {
+			return
+		}
+		if err := updatePageMeta(ctx, shortCode, title, description); err != nil {
+			log.Printf("Failed to update page metadata for %s: %v", shortCode, err)
+		}
+	}()
 
 	// Return response - konsistent mit CloudFront /s/* Route
 	response := map[string]string{
@@ -229,26 +353,36 @@ func handleRedirect(ctx context.Context, request events.APIGatewayV2HTTPRequest,
 		}, nil
 	}
 
-	// Increment click count and update record
-	record.ClickCount++
-	updatedItem, err := attributevalue.MarshalMap(record)
-	if err != nil {
-		log.Printf("Failed to marshal updated record: %v", err)
-	} else {
-		// Update item in DynamoDB with new click count
-		_, err = dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
-			TableName: aws.String(tableName),
-			Item:      updatedItem,
-		})
+	client := useragent.Parse(headerValue(request.Headers, "User-Agent"))
+
+	// Bots and crawlers don't represent real traffic: skip the click count
+	// write and the UrlsAccessed metric so dashboards reflect human usage.
+	if !client.IsBot {
+		record.ClickCount++
+		updatedItem, err := attributevalue.MarshalMap(record)
 		if err != nil {
-			log.Printf("Failed to update click count: %v", err)
+			log.Printf("Failed to marshal updated record: %v", err)
+		} else {
+			// Update item in DynamoDB with new click count
+			_, err = dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName: aws.String(tableName),
+				Item:      updatedItem,
+			})
+			if err != nil {
+				log.Printf("Failed to update click count: %v", err)
+			}
 		}
+
+		// Publish click metric
+		go func() {
+			ctx := context.Background()
+			publishCustomMetric(ctx, "UrlsAccessed", 1)
+		}()
 	}
 
-	// Publish click metric
 	go func() {
 		ctx := context.Background()
-		publishCustomMetric(ctx, "UrlsAccessed", 1)
+		publishClientMetrics(ctx, client)
 	}()
 
 	// Redirect to original URL
@@ -257,6 +391,7 @@ func handleRedirect(ctx context.Context, request events.APIGatewayV2HTTPRequest,
 		redirectHeaders[k] = v
 	}
 	redirectHeaders["Location"] = record.OriginalURL
+	redirectHeaders["X-Shortener-Client"] = formatClientHeader(client)
 
 	return events.APIGatewayV2HTTPResponse{
 		StatusCode: 301,
@@ -284,61 +419,56 @@ func publishCustomMetric(ctx context.Context, metricName string, value float64)
 	return err
 }
 
-func handleMetrics(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
-	// Publish a metric for metrics access
-	go func() {
-		ctx := context.Background()
-		publishCustomMetric(ctx, "MetricsAccessed", 1)
-	}()
+func handleMine(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
+	if !authRouter.Enabled() {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 404,
+			Headers:    headers,
+			Body:       `{"error": "Not found"}`,
+		}, nil
+	}
 
-	// Get actual counts from DynamoDB
-	var urlsCreated int64
-	var urlsAccessed int64
-	var activeUrls int64
+	token := auth.BearerToken(headerValue(request.Headers, "Authorization"))
+	identity, err := authRouter.Authenticate(ctx, token)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 401,
+			Headers:    headers,
+			Body:       `{"error": "Unauthorized"}`,
+		}, nil
+	}
 
-	// Scan the table to get actual metrics
-	scanResult, err := dynamoClient.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(tableName),
+	// Query the owner_sub GSI rather than scanning the whole table - a Scan
+	// here would cost the same as the full-table Scan chunk0-5 replaced for
+	// /metrics, except on a per-caller endpoint where every authenticated
+	// user pays for every URL ever created, not just their own.
+	queryResult, err := dynamoClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(tableName),
+		IndexName:              aws.String(ownerIndexName),
+		KeyConditionExpression: aws.String("owner_sub = :sub"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":sub": &dynamodbtypes.AttributeValueMemberS{Value: identity.Sub},
+		},
 	})
 	if err != nil {
-		log.Printf("Failed to scan DynamoDB: %v", err)
-		// Return mock data if scan fails
-		response := map[string]interface{}{
-			"urls_created":    0,
-			"urls_accessed":   0,
-			"unique_visitors": 0,
-			"active_urls":     0,
-			"timestamp":       time.Now().Format(time.RFC3339),
-			"error":           "Failed to fetch metrics",
-		}
-		responseBody, _ := json.Marshal(response)
+		log.Printf("Failed to query DynamoDB owner index: %v", err)
 		return events.APIGatewayV2HTTPResponse{
-			StatusCode: 200,
+			StatusCode: 500,
 			Headers:    headers,
-			Body:       string(responseBody),
+			Body:       `{"error": "Internal server error"}`,
 		}, nil
 	}
 
-	// Process scan results
-	activeUrls = int64(len(scanResult.Items))
-	for _, item := range scanResult.Items {
+	urls := make([]URLRecord, 0, len(queryResult.Items))
+	for _, item := range queryResult.Items {
 		var record URLRecord
-		err := attributevalue.UnmarshalMap(item, &record)
-		if err == nil {
-			urlsCreated++
-			urlsAccessed += record.ClickCount
+		if err := attributevalue.UnmarshalMap(item, &record); err != nil {
+			continue
 		}
+		urls = append(urls, record)
 	}
 
-	response := map[string]interface{}{
-		"urls_created":    urlsCreated,
-		"urls_accessed":   urlsAccessed,
-		"unique_visitors": urlsAccessed, // Simple approximation
-		"active_urls":     activeUrls,
-		"timestamp":       time.Now().Format(time.RFC3339),
-	}
-
-	responseBody, _ := json.Marshal(response)
+	responseBody, _ := json.Marshal(map[string]interface{}{"urls": urls})
 	return events.APIGatewayV2HTTPResponse{
 		StatusCode: 200,
 		Headers:    headers,
@@ -347,5 +477,5 @@ func handleMetrics(ctx context.Context, request events.APIGatewayV2HTTPRequest,
 }
 
 func main() {
-	lambda.Start(handleRequest)
+	lambda.Start(route)
 }
@@ -88,6 +88,7 @@ func TestHandleRequestValidation(t *testing.T) {
 		{"missing URL", "POST", "/create", `{}`, 400, "URL parameter is required"},
 		{"unsupported scheme", "POST", "/create", `{"url":"ftp://example.com"}`, 400, "Invalid URL"},
 		{"route not found", "GET", "/unknown", "", 404, "Not found"},
+		{"mine without auth connectors configured", "GET", "/mine", "", 404, "Not found"},
 	}
 
 	for _, test := range tests {
@@ -135,13 +136,19 @@ func TestCreateResponseFormat(t *testing.T) {
 
 // Test metrics response format
 func TestMetricsResponseFormat(t *testing.T) {
-	// Sample metrics response
+	// Sample metrics response - reflects the CloudWatch-backed /metrics
+	// shape: a time series per metric plus point-in-time totals.
 	sampleResponse := `{
-		"urls_created": 10,
-		"urls_accessed": 25,
-		"unique_visitors": 15,
-		"active_urls": 8,
-		"timestamp": "2024-01-15T10:00:00Z"
+		"series": [
+			{"metric": "urls_created", "points": [[1705312800, 10]]},
+			{"metric": "urls_accessed", "points": [[1705312800, 25]]},
+			{"metric": "metrics_accessed", "points": [[1705312800, 3]]}
+		],
+		"totals": {
+			"active_urls": 8,
+			"urls_created": 10,
+			"timestamp": "2024-01-15T10:00:00Z"
+		}
 	}`
 
 	var response map[string]interface{}
@@ -150,11 +157,19 @@ func TestMetricsResponseFormat(t *testing.T) {
 		t.Fatalf("Failed to unmarshal metrics: %v", err)
 	}
 
-	requiredFields := []string{"urls_created", "urls_accessed", "unique_visitors", "active_urls", "timestamp"}
+	if _, ok := response["series"]; !ok {
+		t.Error("Metrics response missing field: series")
+	}
+
+	totals, ok := response["totals"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Metrics response missing or malformed field: totals")
+	}
 
-	for _, field := range requiredFields {
-		if _, ok := response[field]; !ok {
-			t.Errorf("Metrics response missing field: %s", field)
+	requiredTotalsFields := []string{"active_urls", "urls_created", "timestamp"}
+	for _, field := range requiredTotalsFields {
+		if _, ok := totals[field]; !ok {
+			t.Errorf("Metrics totals missing field: %s", field)
 		}
 	}
 }
@@ -216,9 +231,3 @@ func isVaildHTTPUrl(raw string) bool {
 	}
 	return parsedURL.Scheme == "http" || parsedURL.Scheme == "https"
 }
-
-// Helper function to generate a short code from a URL
-func generateShortCode(input string) string {
-	hash := sha256.Sum256([]byte(input))
-	return hex.EncodeToString(hash[:])[:8]
-}
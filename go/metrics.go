@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vibtellect/url-shortener-aws/go/auth"
+)
+
+const metricsNamespace = "UrlShortener/Demo"
+
+// Stats counter items: atomic DynamoDB counters maintained alongside the
+// CloudWatch time series, so /metrics doesn't need an expensive table scan
+// to report the current active/total URL counts.
+const (
+	statsActiveKey = "__stats__:active"
+	statsTotalKey  = "__stats__:total"
+)
+
+// MetricPoint is one (timestamp, value) sample in a time series.
+type MetricPoint struct {
+	Timestamp int64
+	Value     float64
+}
+
+// MetricsReader aggregates published CloudWatch metrics over a time range.
+// It is an interface so handleMetrics can be tested against canned data
+// instead of a live CloudWatch endpoint.
+type MetricsReader interface {
+	Query(ctx context.Context, metricNames []string, from, to time.Time, period int32) (map[string][]MetricPoint, error)
+}
+
+// cloudWatchMetricsReader is the production MetricsReader backed by
+// cloudwatch.Client.GetMetricData.
+type cloudWatchMetricsReader struct {
+	client    *cloudwatch.Client
+	namespace string
+}
+
+func (r *cloudWatchMetricsReader) Query(ctx context.Context, metricNames []string, from, to time.Time, period int32) (map[string][]MetricPoint, error) {
+	queries := make([]types.MetricDataQuery, len(metricNames))
+	for i, name := range metricNames {
+		queries[i] = types.MetricDataQuery{
+			Id: aws.String(fmt.Sprintf("m%d", i)),
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String(r.namespace),
+					MetricName: aws.String(name),
+				},
+				Period: aws.Int32(period),
+				Stat:   aws.String("Sum"),
+			},
+			Label: aws.String(name),
+		}
+	}
+
+	out, err := r.client.GetMetricData(ctx, &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(from),
+		EndTime:           aws.Time(to),
+		MetricDataQueries: queries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetMetricData: %w", err)
+	}
+
+	return resultsToSeries(out.MetricDataResults), nil
+}
+
+// resultsToSeries converts CloudWatch's parallel Timestamps/Values slices
+// into the metric-name-keyed map the handler renders as JSON.
+func resultsToSeries(results []types.MetricDataResult) map[string][]MetricPoint {
+	series := make(map[string][]MetricPoint, len(results))
+	for _, r := range results {
+		name := aws.ToString(r.Label)
+		points := make([]MetricPoint, len(r.Values))
+		for i, v := range r.Values {
+			ts := int64(0)
+			if i < len(r.Timestamps) {
+				ts = r.Timestamps[i].Unix()
+			}
+			points[i] = MetricPoint{Timestamp: ts, Value: v}
+		}
+		series[name] = points
+	}
+	return series
+}
+
+var metricNameToKey = map[string]string{
+	"UrlsCreated":     "urls_created",
+	"UrlsAccessed":    "urls_accessed",
+	"MetricsAccessed": "metrics_accessed",
+}
+
+func handleMetrics(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
+	// /metrics reports namespace-wide aggregates (CloudWatch sums and the
+	// global active/total counters), not anything scoped to a single
+	// OwnerSub, so there's nothing to filter per caller. It's still an
+	// admin-level view of the whole deployment's traffic, so require the
+	// same identity /create does whenever auth is configured at all.
+	if authRouter.Enabled() {
+		token := auth.BearerToken(headerValue(request.Headers, "Authorization"))
+		if _, err := authRouter.Authenticate(ctx, token); err != nil {
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 401,
+				Headers:    headers,
+				Body:       `{"error": "Unauthorized"}`,
+			}, nil
+		}
+	}
+
+	// Publish a metric for metrics access
+	go func() {
+		ctx := context.Background()
+		publishCustomMetric(ctx, "MetricsAccessed", 1)
+	}()
+
+	from, to, period, err := parseMetricsWindow(request.QueryStringParameters)
+	if err != nil {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       fmt.Sprintf(`{"error": %q}`, err.Error()),
+		}, nil
+	}
+
+	metricNames := []string{"UrlsCreated", "UrlsAccessed", "MetricsAccessed"}
+	results, err := metricsReader.Query(ctx, metricNames, from, to, period)
+	if err != nil {
+		log.Printf("Failed to query CloudWatch metrics: %v", err)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       `{"error": "Internal server error"}`,
+		}, nil
+	}
+
+	series := make([]map[string]interface{}, 0, len(metricNames))
+	for _, name := range metricNames {
+		points := make([][2]float64, 0, len(results[name]))
+		for _, p := range results[name] {
+			points = append(points, [2]float64{float64(p.Timestamp), p.Value})
+		}
+		series = append(series, map[string]interface{}{
+			"metric": metricNameToKey[name],
+			"points": points,
+		})
+	}
+
+	activeUrls, err := getStat(ctx, statsActiveKey)
+	if err != nil {
+		log.Printf("Failed to read active_urls counter: %v", err)
+	}
+	urlsCreated, err := getStat(ctx, statsTotalKey)
+	if err != nil {
+		log.Printf("Failed to read urls_created counter: %v", err)
+	}
+
+	response := map[string]interface{}{
+		"series": series,
+		"totals": map[string]interface{}{
+			"active_urls":  activeUrls,
+			"urls_created": urlsCreated,
+			"timestamp":    time.Now().Format(time.RFC3339),
+		},
+	}
+
+	responseBody, _ := json.Marshal(response)
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: 200,
+		Headers:    headers,
+		Body:       string(responseBody),
+	}, nil
+}
+
+// parseMetricsWindow reads ?from=&to=&period= (RFC3339 timestamps, period
+// in seconds), defaulting to the last hour at a 60s period.
+func parseMetricsWindow(params map[string]string) (from, to time.Time, period int32, err error) {
+	to = time.Now()
+	from = to.Add(-time.Hour)
+	period = 60
+
+	if v := params["from"]; v != "" {
+		from, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid from: %w", err)
+		}
+	}
+	if v := params["to"]; v != "" {
+		to, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid to: %w", err)
+		}
+	}
+	if v := params["period"]; v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p <= 0 {
+			return time.Time{}, time.Time{}, 0, fmt.Errorf("invalid period: must be a positive integer")
+		}
+		period = int32(p)
+	}
+
+	return from, to, period, nil
+}
+
+// incrementStat adjusts a stats counter item by delta, creating it on first
+// use. Errors are logged by the caller, not returned as request failures:
+// the counters are a best-effort convenience, not the system of record.
+func incrementStat(ctx context.Context, key string, delta int64) error {
+	_, err := dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: key},
+		},
+		UpdateExpression: aws.String("ADD counter :delta"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":delta": &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(delta, 10)},
+		},
+	})
+	return err
+}
+
+func getStat(ctx context.Context, key string) (int64, error) {
+	out, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: key},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	if out.Item == nil {
+		return 0, nil
+	}
+	attr, ok := out.Item["counter"].(*dynamodbtypes.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	return strconv.ParseInt(attr.Value, 10, 64)
+}
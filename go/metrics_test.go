@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// fakeMetricsReader returns canned points per metric name, standing in for
+// a live CloudWatch GetMetricData call.
+type fakeMetricsReader struct {
+	points map[string][]MetricPoint
+}
+
+func (f *fakeMetricsReader) Query(ctx context.Context, metricNames []string, from, to time.Time, period int32) (map[string][]MetricPoint, error) {
+	out := make(map[string][]MetricPoint, len(metricNames))
+	for _, name := range metricNames {
+		out[name] = f.points[name]
+	}
+	return out, nil
+}
+
+func TestFakeMetricsReaderQuery(t *testing.T) {
+	reader := &fakeMetricsReader{
+		points: map[string][]MetricPoint{
+			"UrlsCreated": {{Timestamp: 1705312800, Value: 10}},
+		},
+	}
+
+	results, err := reader.Query(context.Background(), []string{"UrlsCreated", "UrlsAccessed"}, time.Now().Add(-time.Hour), time.Now(), 60)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(results["UrlsCreated"]) != 1 || results["UrlsCreated"][0].Value != 10 {
+		t.Errorf("expected a single UrlsCreated point with value 10, got %+v", results["UrlsCreated"])
+	}
+	if len(results["UrlsAccessed"]) != 0 {
+		t.Errorf("expected no UrlsAccessed points, got %+v", results["UrlsAccessed"])
+	}
+}
+
+func TestResultsToSeries(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	results := []types.MetricDataResult{
+		{
+			Label:      strPtr("UrlsCreated"),
+			Timestamps: []time.Time{now},
+			Values:     []float64{10},
+		},
+		{
+			Label: strPtr("UrlsAccessed"),
+		},
+	}
+
+	series := resultsToSeries(results)
+
+	created := series["UrlsCreated"]
+	if len(created) != 1 || created[0].Value != 10 || created[0].Timestamp != now.Unix() {
+		t.Errorf("unexpected UrlsCreated series: %+v", created)
+	}
+	if len(series["UrlsAccessed"]) != 0 {
+		t.Errorf("expected empty UrlsAccessed series, got %+v", series["UrlsAccessed"])
+	}
+}
+
+func TestParseMetricsWindowDefaults(t *testing.T) {
+	from, to, period, err := parseMetricsWindow(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if period != 60 {
+		t.Errorf("expected default period 60, got %d", period)
+	}
+	if !to.After(from) {
+		t.Errorf("expected to (%v) to be after from (%v)", to, from)
+	}
+}
+
+func TestParseMetricsWindowExplicit(t *testing.T) {
+	params := map[string]string{
+		"from":   "2024-01-15T09:00:00Z",
+		"to":     "2024-01-15T10:00:00Z",
+		"period": "300",
+	}
+
+	from, to, period, err := parseMetricsWindow(params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if period != 300 {
+		t.Errorf("expected period 300, got %d", period)
+	}
+	if from.Format(time.RFC3339) != "2024-01-15T09:00:00Z" || to.Format(time.RFC3339) != "2024-01-15T10:00:00Z" {
+		t.Errorf("unexpected window: from=%v to=%v", from, to)
+	}
+}
+
+func TestParseMetricsWindowInvalid(t *testing.T) {
+	tests := []map[string]string{
+		{"from": "not-a-time"},
+		{"to": "not-a-time"},
+		{"period": "not-a-number"},
+		{"period": "0"},
+	}
+
+	for _, params := range tests {
+		if _, _, _, err := parseMetricsWindow(params); err == nil {
+			t.Errorf("expected an error for params %+v", params)
+		}
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// handlePreview serves GET /s/{shortCode}/preview: a link-preview-style
+// summary of where a short URL points, without redirecting the caller.
+func handlePreview(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
+	shortCode := request.PathParameters["shortCode"]
+
+	record, found, err := getURLRecord(ctx, shortCode)
+	if err != nil {
+		log.Printf("Failed to get item from DynamoDB: %v", err)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       `{"error": "Internal server error"}`,
+		}, nil
+	}
+	if !found {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 404,
+			Headers:    headers,
+			Body:       `{"error": "Short URL not found"}`,
+		}, nil
+	}
+
+	responseBody, _ := json.Marshal(buildPreviewBody(record))
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: 200,
+		Headers:    headers,
+		Body:       string(responseBody),
+	}, nil
+}
+
+// buildPreviewBody shapes a URLRecord into the /preview JSON payload; kept
+// separate from handlePreview so it can be unit tested without DynamoDB.
+func buildPreviewBody(record URLRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"original_url": record.OriginalURL,
+		"click_count":  record.ClickCount,
+		"created_at":   record.CreatedAt,
+		"expires_at":   time.Unix(record.ExpiresAt, 0).Format(time.RFC3339),
+		"title":        record.Title,
+		"description":  record.Description,
+	}
+}
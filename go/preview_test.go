@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildPreviewBodyShape(t *testing.T) {
+	record := URLRecord{
+		ShortCode:   "abc123",
+		OriginalURL: "https://example.com",
+		ExpiresAt:   time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC).Unix(),
+		CreatedAt:   "2024-01-08T10:00:00Z",
+		ClickCount:  5,
+		Title:       "Example Site",
+		Description: "An example",
+	}
+
+	body := buildPreviewBody(record)
+
+	requiredFields := []string{"original_url", "click_count", "created_at", "expires_at", "title", "description"}
+	for _, field := range requiredFields {
+		if _, ok := body[field]; !ok {
+			t.Errorf("preview body missing field: %s", field)
+		}
+	}
+
+	if body["original_url"] != record.OriginalURL {
+		t.Errorf("expected original_url %q, got %v", record.OriginalURL, body["original_url"])
+	}
+	if body["click_count"] != record.ClickCount {
+		t.Errorf("expected click_count %d, got %v", record.ClickCount, body["click_count"])
+	}
+	if body["expires_at"] != "2024-01-15T10:00:00Z" {
+		t.Errorf("expected expires_at to be RFC3339-formatted, got %v", body["expires_at"])
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/vibtellect/url-shortener-aws/go/qrcode"
+)
+
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+// handleQR serves GET /s/{shortCode}/qr?size=256&format=png|svg, a QR
+// code encoding the short URL itself rather than the original target -
+// so the image keeps working even if the destination later changes.
+func handleQR(ctx context.Context, request events.APIGatewayV2HTTPRequest, headers map[string]string) (events.APIGatewayV2HTTPResponse, error) {
+	shortCode := request.PathParameters["shortCode"]
+
+	record, found, err := getURLRecord(ctx, shortCode)
+	if err != nil {
+		log.Printf("Failed to get item from DynamoDB: %v", err)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       `{"error": "Internal server error"}`,
+		}, nil
+	}
+	if !found {
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 404,
+			Headers:    headers,
+			Body:       `{"error": "Short URL not found"}`,
+		}, nil
+	}
+
+	size := defaultQRSize
+	if v := request.QueryStringParameters["size"]; v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			size = parsed
+		}
+	}
+	size = clampQRSize(size)
+	format := request.QueryStringParameters["format"]
+	if format == "" {
+		format = "png"
+	}
+
+	shortURL := fmt.Sprintf("%s/s/%s", baseURL, record.ShortCode)
+	code, err := qrcode.Encode([]byte(shortURL))
+	if err != nil {
+		log.Printf("Failed to encode QR code: %v", err)
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 500,
+			Headers:    headers,
+			Body:       `{"error": "Internal server error"}`,
+		}, nil
+	}
+
+	switch format {
+	case "svg":
+		svgHeaders := withContentType(headers, "image/svg+xml")
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 200,
+			Headers:    svgHeaders,
+			Body:       code.SVG(size),
+		}, nil
+	case "png":
+		png, err := code.PNG(size)
+		if err != nil {
+			log.Printf("Failed to render QR PNG: %v", err)
+			return events.APIGatewayV2HTTPResponse{
+				StatusCode: 500,
+				Headers:    headers,
+				Body:       `{"error": "Internal server error"}`,
+			}, nil
+		}
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode:      200,
+			Headers:         withContentType(headers, "image/png"),
+			Body:            base64.StdEncoding.EncodeToString(png),
+			IsBase64Encoded: true,
+		}, nil
+	default:
+		return events.APIGatewayV2HTTPResponse{
+			StatusCode: 400,
+			Headers:    headers,
+			Body:       `{"error": "Unsupported format: must be png or svg"}`,
+		}, nil
+	}
+}
+
+// clampQRSize bounds a caller-supplied QR size to [minQRSize, maxQRSize] so
+// an unbounded size can't force a multi-gigabyte image allocation.
+func clampQRSize(size int) int {
+	if size < minQRSize {
+		return minQRSize
+	}
+	if size > maxQRSize {
+		return maxQRSize
+	}
+	return size
+}
+
+func withContentType(headers map[string]string, contentType string) map[string]string {
+	out := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		out[k] = v
+	}
+	out["Content-Type"] = contentType
+	return out
+}
+
+// getURLRecord fetches and unmarshals a short code's DynamoDB item. The
+// bool return is false (with a nil error) when the code doesn't exist or
+// has expired - DynamoDB TTL deletion lags real time by up to 48h, so
+// callers can't rely on the item simply being gone yet. This keeps QR and
+// preview agreeing with handleRedirect's own expiry check on what "still
+// alive" means.
+func getURLRecord(ctx context.Context, shortCode string) (URLRecord, bool, error) {
+	result, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: shortCode},
+		},
+	})
+	if err != nil {
+		return URLRecord{}, false, err
+	}
+	if result.Item == nil {
+		return URLRecord{}, false, nil
+	}
+
+	var record URLRecord
+	if err := attributevalue.UnmarshalMap(result.Item, &record); err != nil {
+		return URLRecord{}, false, err
+	}
+	if time.Now().Unix() > record.ExpiresAt {
+		return URLRecord{}, false, nil
+	}
+	return record, true, nil
+}
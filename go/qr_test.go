@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestClampQRSize(t *testing.T) {
+	cases := []struct {
+		size int
+		want int
+	}{
+		{0, minQRSize},
+		{1, minQRSize},
+		{minQRSize, minQRSize},
+		{256, 256},
+		{maxQRSize, maxQRSize},
+		{100000000, maxQRSize},
+	}
+
+	for _, c := range cases {
+		if got := clampQRSize(c.size); got != c.want {
+			t.Errorf("clampQRSize(%d) = %d, want %d", c.size, got, c.want)
+		}
+	}
+}
+
+func TestWithContentType(t *testing.T) {
+	headers := map[string]string{"Content-Type": "application/json"}
+	out := withContentType(headers, "image/png")
+
+	if out["Content-Type"] != "image/png" {
+		t.Errorf("expected Content-Type image/png, got %q", out["Content-Type"])
+	}
+	if headers["Content-Type"] != "application/json" {
+		t.Error("withContentType should not mutate the input map")
+	}
+}
@@ -0,0 +1,42 @@
+package qrcode
+
+// bitWriter accumulates bits MSB-first into a byte slice, padding the
+// final byte with zero bits.
+type bitWriter struct {
+	bytes    []byte
+	bitCount int
+}
+
+func (w *bitWriter) len() int {
+	return w.bitCount
+}
+
+func (w *bitWriter) writeBits(value uint32, count int) {
+	for i := count - 1; i >= 0; i-- {
+		bit := (value>>uint(i))&1 == 1
+		byteIndex := w.bitCount / 8
+		for byteIndex >= len(w.bytes) {
+			w.bytes = append(w.bytes, 0)
+		}
+		if bit {
+			w.bytes[byteIndex] |= 1 << uint(7-w.bitCount%8)
+		}
+		w.bitCount++
+	}
+}
+
+func (w *bitWriter) padToByte() {
+	if pad := w.bitCount % 8; pad != 0 {
+		w.writeBits(0, 8-pad)
+	}
+}
+
+// bitAt reports the value of bit i (0 = most significant) of data,
+// treated as a big-endian bit string.
+func bitAt(data []byte, i int) bool {
+	byteIndex := i / 8
+	if byteIndex >= len(data) {
+		return false
+	}
+	return (data[byteIndex]>>uint(7-i%8))&1 == 1
+}
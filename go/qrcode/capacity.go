@@ -0,0 +1,79 @@
+package qrcode
+
+// This package only targets error correction level M, the level the
+// /qr endpoint needs: enough resilience to survive a printed sticker or
+// a phone camera at an angle without paying for level Q/H's extra
+// redundancy or level L's larger minimum version for the same data.
+
+// blockGroup describes one group of equally-sized RS blocks within a
+// version's codeword layout (some versions split data across two
+// differently-sized groups of blocks).
+type blockGroup struct {
+	count        int
+	dataPerBlock int
+}
+
+// versionSpec holds the fixed structural constants for one QR version at
+// error correction level M, per the QR Code standard's tables.
+type versionSpec struct {
+	version         int
+	size            int // modules per side
+	ecPerBlock      int
+	groups          []blockGroup
+	alignmentCoords []int
+	remainderBits   int
+}
+
+func (v versionSpec) dataCodewords() int {
+	total := 0
+	for _, g := range v.groups {
+		total += g.count * g.dataPerBlock
+	}
+	return total
+}
+
+func (v versionSpec) totalBlocks() int {
+	total := 0
+	for _, g := range v.groups {
+		total += g.count
+	}
+	return total
+}
+
+// charCountBits is the length of the byte-mode character count indicator
+// for this version.
+func (v versionSpec) charCountBits() int {
+	if v.version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// versions lists every supported version (1-10) at error correction
+// level M, ordered from smallest to largest capacity.
+var versions = []versionSpec{
+	{1, 21, 10, []blockGroup{{1, 16}}, nil, 0},
+	{2, 25, 16, []blockGroup{{1, 28}}, []int{6, 18}, 7},
+	{3, 29, 26, []blockGroup{{1, 44}}, []int{6, 22}, 7},
+	{4, 33, 18, []blockGroup{{2, 32}}, []int{6, 26}, 7},
+	{5, 37, 24, []blockGroup{{2, 43}}, []int{6, 30}, 7},
+	{6, 41, 16, []blockGroup{{4, 27}}, []int{6, 34}, 7},
+	{7, 45, 18, []blockGroup{{4, 31}}, []int{6, 22, 38}, 0},
+	{8, 49, 22, []blockGroup{{2, 38}, {2, 39}}, []int{6, 24, 42}, 0},
+	{9, 53, 22, []blockGroup{{3, 36}, {2, 37}}, []int{6, 26, 46}, 0},
+	{10, 57, 26, []blockGroup{{4, 43}, {1, 44}}, []int{6, 28, 50}, 0},
+}
+
+// selectVersion picks the smallest version (1-10, level M) that can hold
+// byteLen bytes of byte-mode data, including the mode indicator and
+// character count header.
+func selectVersion(byteLen int) (versionSpec, error) {
+	for _, v := range versions {
+		headerBits := 4 + v.charCountBits()
+		capacityBits := v.dataCodewords() * 8
+		if headerBits+8*byteLen <= capacityBits {
+			return v, nil
+		}
+	}
+	return versionSpec{}, errTooLong
+}
@@ -0,0 +1,267 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// The other tests in this package check the generated grid's shape and
+// rendering, but never that a scanner could actually read the content
+// back out of it. This file hand-rolls just enough of the QR decode side
+// (mask removal, codeword extraction, RS integrity check, bitstream
+// parsing) to catch encoding bugs - like a generator polynomial built in
+// the wrong coefficient order - that produce a well-formed-looking but
+// unscannable symbol.
+
+// versionForSize finds the versionSpec matching a decoded symbol's module
+// count.
+func versionForSize(size int) (versionSpec, error) {
+	for _, v := range versions {
+		if v.size == size {
+			return v, nil
+		}
+	}
+	return versionSpec{}, fmt.Errorf("no known version for size %d", size)
+}
+
+// decodeFormatInfo reads the top-left format info copy and returns the
+// mask pattern it encodes, undoing drawFormatInfo's XOR mask. It trusts
+// the BCH error correction bits are clean, since this package only ever
+// produces codes at level M.
+func decodeFormatInfo(m *matrix) int {
+	getBit := func(i int) bool {
+		switch {
+		case i <= 5:
+			return m.modules[8][i]
+		case i == 6:
+			return m.modules[8][7]
+		case i == 7:
+			return m.modules[8][8]
+		case i == 8:
+			return m.modules[7][8]
+		default:
+			return m.modules[14-i][8]
+		}
+	}
+
+	var bits int
+	for i := 0; i < 15; i++ {
+		if getBit(i) {
+			bits |= 1 << uint(i)
+		}
+	}
+	data := (bits ^ formatXORMask) >> 10
+	return data & 0b111
+}
+
+// extractCodewords reverses drawCodewords: it walks the same zigzag
+// traversal and reads each non-function module into a bit string, then
+// packs it into bytes.
+func extractCodewords(m *matrix, totalCodewords int) []byte {
+	bits := make([]bool, 0, totalCodewords*8+m.version.remainderBits)
+
+	upward := true
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		rows := make([]int, m.size)
+		for i := range rows {
+			rows[i] = i
+		}
+		if upward {
+			reverse(rows)
+		}
+		for _, row := range rows {
+			for _, col := range []int{right, right - 1} {
+				if m.isFunction[row][col] {
+					continue
+				}
+				bits = append(bits, m.modules[row][col])
+			}
+		}
+		upward = !upward
+	}
+
+	out := make([]byte, totalCodewords)
+	for i := 0; i < totalCodewords*8 && i < len(bits); i++ {
+		if bits[i] {
+			out[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return out
+}
+
+// deinterleave reverses interleave: it splits codewords back into the
+// per-block data and EC codewords for v's block layout.
+func deinterleave(codewords []byte, v versionSpec) (dataBlocks, ecBlocks [][]byte) {
+	blockSizes := make([]int, 0, v.totalBlocks())
+	for _, g := range v.groups {
+		for i := 0; i < g.count; i++ {
+			blockSizes = append(blockSizes, g.dataPerBlock)
+		}
+	}
+
+	dataBlocks = make([][]byte, len(blockSizes))
+	for i := range dataBlocks {
+		dataBlocks[i] = make([]byte, 0, blockSizes[i])
+	}
+
+	maxData := 0
+	for _, s := range blockSizes {
+		if s > maxData {
+			maxData = s
+		}
+	}
+
+	pos := 0
+	for i := 0; i < maxData; i++ {
+		for b, size := range blockSizes {
+			if i < size {
+				dataBlocks[b] = append(dataBlocks[b], codewords[pos])
+				pos++
+			}
+		}
+	}
+
+	ecBlocks = make([][]byte, len(blockSizes))
+	for i := range ecBlocks {
+		ecBlocks[i] = make([]byte, 0, v.ecPerBlock)
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for b := range blockSizes {
+			ecBlocks[b] = append(ecBlocks[b], codewords[pos])
+			pos++
+		}
+	}
+
+	return dataBlocks, ecBlocks
+}
+
+// decodeByteModeMessage parses a byte-mode bitstream (mode indicator,
+// character count, payload) out of the concatenated data blocks.
+func decodeByteModeMessage(data []byte, v versionSpec) ([]byte, error) {
+	readBit := func(i int) bool { return bitAt(data, i) }
+	readBits := func(start, count int) int {
+		n := 0
+		for i := 0; i < count; i++ {
+			n <<= 1
+			if readBit(start + i) {
+				n |= 1
+			}
+		}
+		return n
+	}
+
+	mode := readBits(0, 4)
+	if mode != 0b0100 {
+		return nil, fmt.Errorf("expected byte-mode indicator 0b0100, got %04b", mode)
+	}
+
+	charCountBits := v.charCountBits()
+	length := readBits(4, charCountBits)
+
+	start := 4 + charCountBits
+	if start+length*8 > len(data)*8 {
+		return nil, fmt.Errorf("declared length %d exceeds available data", length)
+	}
+
+	out := make([]byte, length)
+	for i := 0; i < length; i++ {
+		out[i] = byte(readBits(start+i*8, 8))
+	}
+	return out, nil
+}
+
+// decode is the test-only inverse of Encode: it reconstructs the original
+// byte-mode payload from a generated Code, verifying every RS block's
+// error correction codewords along the way.
+func decode(code *Code) ([]byte, error) {
+	v, err := versionForSize(code.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	m := newMatrix(v)
+	m.drawFunctionPatterns()
+	m.modules = code.Modules
+
+	mask := decodeFormatInfo(m)
+	for r := 0; r < m.size; r++ {
+		for c := 0; c < m.size; c++ {
+			if !m.isFunction[r][c] && maskFunc(mask, r, c) {
+				m.modules[r][c] = !m.modules[r][c]
+			}
+		}
+	}
+
+	codewords := extractCodewords(m, v.dataCodewords()+v.ecPerBlock*v.totalBlocks())
+	dataBlocks, ecBlocks := deinterleave(codewords, v)
+
+	for i, block := range dataBlocks {
+		if err := checkRSSyndromes(block, ecBlocks[i]); err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+	}
+
+	var data []byte
+	for _, block := range dataBlocks {
+		data = append(data, block...)
+	}
+	return decodeByteModeMessage(data, v)
+}
+
+// evalPoly evaluates a polynomial with high-degree-first coefficients at x
+// in GF(256), via Horner's method.
+func evalPoly(coeffs []byte, x byte) byte {
+	var result byte
+	for _, c := range coeffs {
+		result = gfMul(result, x) ^ c
+	}
+	return result
+}
+
+// checkRSSyndromes is the same check a real Reed-Solomon decoder runs
+// before attempting correction: a valid codeword (data followed by its EC
+// codewords, read as one high-degree-first polynomial) must evaluate to
+// zero at every root alpha^0..alpha^(ecCount-1) the generator polynomial
+// was built from. This is deliberately independent of rsEncode/
+// rsGeneratorPoly's own arithmetic - it would have caught the generator
+// polynomial coefficient-order bug those functions once had, which a
+// check that merely re-ran rsEncode and compared outputs would not.
+func checkRSSyndromes(data, ec []byte) error {
+	codeword := append(append([]byte{}, data...), ec...)
+	for i := range ec {
+		if v := evalPoly(codeword, gfExp[i]); v != 0 {
+			return fmt.Errorf("non-zero syndrome at root alpha^%d: %d", i, v)
+		}
+	}
+	return nil
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	cases := [][]byte{
+		[]byte("a"),
+		[]byte("https://short.example/s/abc123"),
+		[]byte("https://url-shortener.vibtellect.de/s/abc123?utm_source=qr&utm_medium=print"),
+		bytes.Repeat([]byte("x"), 200),
+	}
+
+	for _, input := range cases {
+		t.Run(fmt.Sprintf("%d bytes", len(input)), func(t *testing.T) {
+			code, err := Encode(input)
+			if err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+
+			got, err := decode(code)
+			if err != nil {
+				t.Fatalf("decode failed: %v", err)
+			}
+			if !bytes.Equal(got, input) {
+				t.Errorf("round trip mismatch: got %q, want %q", got, input)
+			}
+		})
+	}
+}
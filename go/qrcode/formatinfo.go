@@ -0,0 +1,68 @@
+package qrcode
+
+// Fixed BCH generator/mask constants from ISO/IEC 18004 for the 15-bit
+// format info field and the 18-bit version info field (versions 7+).
+const (
+	formatGeneratorPoly  = 0x537
+	formatXORMask        = 0x5412
+	versionGeneratorPoly = 0x1F25
+
+	// ecLevelMBits is the 2-bit error correction level indicator for
+	// level M, the only level this package supports.
+	ecLevelMBits = 0b00
+)
+
+// drawFormatInfo computes the BCH(15,5) error-corrected format info for
+// (level M, mask) and writes both copies into their reserved locations.
+func (m *matrix) drawFormatInfo(mask int) {
+	data := ecLevelMBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * formatGeneratorPoly)
+	}
+	bits := (data<<10 | rem) ^ formatXORMask
+
+	getBit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, getBit(i))
+	}
+	m.set(8, 7, getBit(6))
+	m.set(8, 8, getBit(7))
+	m.set(7, 8, getBit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, getBit(i))
+	}
+
+	for i := 0; i <= 7; i++ {
+		m.set(8, m.size-1-i, getBit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(m.size-15+i, 8, getBit(i))
+	}
+
+	m.set(m.size-8, 8, true) // dark module
+}
+
+// drawVersionInfo writes the 18-bit BCH(18,6) version info block
+// required for versions 7 and up, in both of its mirrored locations.
+func (m *matrix) drawVersionInfo() {
+	if m.version.version < 7 {
+		return
+	}
+
+	v := m.version.version
+	rem := v
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * versionGeneratorPoly)
+	}
+	bits := v<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		bit := (bits>>uint(i))&1 == 1
+		a := m.size - 11 + i%3
+		b := i / 3
+		m.set(b, a, bit)
+		m.set(a, b, bit)
+	}
+}
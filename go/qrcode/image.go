@@ -0,0 +1,51 @@
+package qrcode
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// quietZone is the number of light modules of margin required around
+// the symbol by the QR standard so scanners can find the finder
+// patterns reliably.
+const quietZone = 4
+
+// PNG renders the code as a PNG image scaled so the whole (quiet zone
+// included) symbol is approximately targetSize pixels square.
+func (c *Code) PNG(targetSize int) ([]byte, error) {
+	scale := targetSize / (c.Size + 2*quietZone)
+	if scale < 1 {
+		scale = 1
+	}
+	pixels := (c.Size + 2*quietZone) * scale
+
+	img := image.NewGray(image.Rect(0, 0, pixels, pixels))
+	for y := 0; y < pixels; y++ {
+		for x := 0; x < pixels; x++ {
+			img.SetGray(x, y, color.Gray{Y: 0xFF})
+		}
+	}
+
+	for row := 0; row < c.Size; row++ {
+		for col := 0; col < c.Size; col++ {
+			if !c.Modules[row][col] {
+				continue
+			}
+			x0 := (col + quietZone) * scale
+			y0 := (row + quietZone) * scale
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray(x0+dx, y0+dy, color.Gray{Y: 0x00})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
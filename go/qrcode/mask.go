@@ -0,0 +1,179 @@
+package qrcode
+
+// maskFunc returns one of the 8 standard QR data masks, each a function
+// of a module's (row, col) position.
+func maskFunc(pattern, row, col int) bool {
+	switch pattern {
+	case 0:
+		return (row+col)%2 == 0
+	case 1:
+		return row%2 == 0
+	case 2:
+		return col%3 == 0
+	case 3:
+		return (row+col)%3 == 0
+	case 4:
+		return (row/2+col/3)%2 == 0
+	case 5:
+		return (row*col)%2+(row*col)%3 == 0
+	case 6:
+		return ((row*col)%2+(row*col)%3)%2 == 0
+	default:
+		return ((row+col)%2+(row*col)%3)%2 == 0
+	}
+}
+
+// chooseBestMask tries all 8 masks and returns the one with the lowest
+// ISO/IEC 18004 penalty score, applied to a scratch copy so the matrix
+// itself isn't mutated until the winner is known.
+func (m *matrix) chooseBestMask() int {
+	best, bestScore := 0, -1
+	for pattern := 0; pattern < 8; pattern++ {
+		score := m.penaltyWithMask(pattern)
+		if bestScore == -1 || score < bestScore {
+			best, bestScore = pattern, score
+		}
+	}
+	return best
+}
+
+func (m *matrix) penaltyWithMask(pattern int) int {
+	trial := make([][]bool, m.size)
+	for r := range trial {
+		trial[r] = make([]bool, m.size)
+		for c := 0; c < m.size; c++ {
+			trial[r][c] = m.modules[r][c]
+			if !m.isFunction[r][c] && maskFunc(pattern, r, c) {
+				trial[r][c] = !trial[r][c]
+			}
+		}
+	}
+	return penaltyScore(trial)
+}
+
+// applyMask permanently XORs the chosen mask into every non-function
+// module.
+func (m *matrix) applyMask(pattern int) {
+	for r := 0; r < m.size; r++ {
+		for c := 0; c < m.size; c++ {
+			if !m.isFunction[r][c] && maskFunc(pattern, r, c) {
+				m.modules[r][c] = !m.modules[r][c]
+			}
+		}
+	}
+}
+
+// finderLikePattern is the 1:1:3:1:1 ratio run (surrounded by 4 light
+// modules on one side) that rules 3 of the penalty score look for.
+var finderLikePattern = []bool{true, false, true, true, true, false, true, false, false, false, false}
+
+func penaltyScore(grid [][]bool) int {
+	size := len(grid)
+	score := 0
+
+	// Rule 1: runs of 5+ same-colored modules in a row or column.
+	for r := 0; r < size; r++ {
+		score += runPenalty(rowOf(grid, r))
+	}
+	for c := 0; c < size; c++ {
+		score += runPenalty(colOf(grid, c))
+	}
+
+	// Rule 2: 2x2 blocks of the same color.
+	for r := 0; r < size-1; r++ {
+		for c := 0; c < size-1; c++ {
+			v := grid[r][c]
+			if grid[r][c+1] == v && grid[r+1][c] == v && grid[r+1][c+1] == v {
+				score += 3
+			}
+		}
+	}
+
+	// Rule 3: finder-like 1:1:3:1:1 patterns.
+	for r := 0; r < size; r++ {
+		score += finderPatternPenalty(rowOf(grid, r))
+	}
+	for c := 0; c < size; c++ {
+		score += finderPatternPenalty(colOf(grid, c))
+	}
+
+	// Rule 4: overall dark/light balance, penalized the further it
+	// drifts from 50%.
+	dark := 0
+	for r := 0; r < size; r++ {
+		for c := 0; c < size; c++ {
+			if grid[r][c] {
+				dark++
+			}
+		}
+	}
+	percent := dark * 100 / (size * size)
+	deviation := percent - 50
+	if deviation < 0 {
+		deviation = -deviation
+	}
+	score += (deviation / 5) * 10
+
+	return score
+}
+
+func rowOf(grid [][]bool, r int) []bool {
+	return grid[r]
+}
+
+func colOf(grid [][]bool, c int) []bool {
+	col := make([]bool, len(grid))
+	for r := range grid {
+		col[r] = grid[r][c]
+	}
+	return col
+}
+
+func runPenalty(line []bool) int {
+	score := 0
+	runLen := 1
+	for i := 1; i < len(line); i++ {
+		if line[i] == line[i-1] {
+			runLen++
+			continue
+		}
+		if runLen >= 5 {
+			score += 3 + (runLen - 5)
+		}
+		runLen = 1
+	}
+	if runLen >= 5 {
+		score += 3 + (runLen - 5)
+	}
+	return score
+}
+
+func finderPatternPenalty(line []bool) int {
+	score := 0
+	n := len(finderLikePattern)
+	for i := 0; i+n <= len(line); i++ {
+		if matchesPattern(line[i:i+n], finderLikePattern) || matchesReversedPattern(line[i:i+n], finderLikePattern) {
+			score += 40
+		}
+	}
+	return score
+}
+
+func matchesPattern(window, pattern []bool) bool {
+	for i := range pattern {
+		if window[i] != pattern[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesReversedPattern(window, pattern []bool) bool {
+	n := len(pattern)
+	for i := range pattern {
+		if window[i] != pattern[n-1-i] {
+			return false
+		}
+	}
+	return true
+}
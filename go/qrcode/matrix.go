@@ -0,0 +1,172 @@
+package qrcode
+
+// matrix is the in-progress module grid for one QR symbol, plus a
+// parallel grid marking which modules belong to fixed function patterns
+// (finders, timing, alignment, format/version info) and so must never be
+// touched by data placement or masking.
+type matrix struct {
+	version    versionSpec
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newMatrix(v versionSpec) *matrix {
+	m := &matrix{version: v, size: v.size}
+	m.modules = make([][]bool, m.size)
+	m.isFunction = make([][]bool, m.size)
+	for i := range m.modules {
+		m.modules[i] = make([]bool, m.size)
+		m.isFunction[i] = make([]bool, m.size)
+	}
+	return m
+}
+
+func (m *matrix) set(row, col int, dark bool) {
+	m.modules[row][col] = dark
+	m.isFunction[row][col] = true
+}
+
+// drawFunctionPatterns lays down every fixed structure except the format
+// and version info, which can't be finalized until the mask is chosen.
+func (m *matrix) drawFunctionPatterns() {
+	m.drawFinder(0, 0)
+	m.drawFinder(0, m.size-7)
+	m.drawFinder(m.size-7, 0)
+
+	for i := 8; i < m.size-8; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+
+	for _, r := range m.version.alignmentCoords {
+		for _, c := range m.version.alignmentCoords {
+			if m.overlapsFinder(r, c) {
+				continue
+			}
+			m.drawAlignment(r, c)
+		}
+	}
+
+	// Reserve the format info areas so codeword placement skips them;
+	// the real bits are written later by drawFormatInfo.
+	for i := 0; i < 9; i++ {
+		if i != 6 {
+			m.set(8, i, false)
+			m.set(i, 8, false)
+		}
+	}
+	for i := 0; i < 8; i++ {
+		m.set(8, m.size-1-i, false)
+		m.set(m.size-1-i, 8, false)
+	}
+	m.set(m.size-8, 8, true) // dark module, fixed regardless of mask
+
+	if m.version.version >= 7 {
+		for i := 0; i < 6; i++ {
+			for j := 0; j < 3; j++ {
+				m.set(m.size-11+j, i, false)
+				m.set(i, m.size-11+j, false)
+			}
+		}
+	}
+}
+
+func (m *matrix) overlapsFinder(r, c int) bool {
+	const pad = 8
+	corners := [][2]int{{0, 0}, {0, m.size - 1}, {m.size - 1, 0}}
+	for _, corner := range corners {
+		if abs(r-corner[0]) < pad && abs(c-corner[1]) < pad {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func (m *matrix) drawFinder(row, col int) {
+	for dr := -1; dr <= 7; dr++ {
+		for dc := -1; dc <= 7; dc++ {
+			r, c := row+dr, col+dc
+			if r < 0 || r >= m.size || c < 0 || c >= m.size {
+				continue
+			}
+			m.set(r, c, finderDark(dr, dc))
+		}
+	}
+}
+
+// finderDark reports whether (dr, dc), relative to the finder's top-left
+// corner, falls in the dark ring, the light separator, or the dark core.
+func finderDark(dr, dc int) bool {
+	if dr < 0 || dr > 6 || dc < 0 || dc > 6 {
+		return false // one-module light separator border
+	}
+	if dr == 0 || dr == 6 || dc == 0 || dc == 6 {
+		return true
+	}
+	if dr == 1 || dr == 5 || dc == 1 || dc == 5 {
+		return false
+	}
+	return true
+}
+
+func (m *matrix) drawAlignment(row, col int) {
+	for dr := -2; dr <= 2; dr++ {
+		for dc := -2; dc <= 2; dc++ {
+			d := maxInt(abs(dr), abs(dc))
+			m.set(row+dr, col+dc, d != 1)
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// drawCodewords places the interleaved codeword bits into every
+// non-function module, following the standard up/down zigzag across
+// column pairs from the bottom-right corner, skipping the vertical
+// timing pattern column.
+func (m *matrix) drawCodewords(data []byte) {
+	bitIndex := 0
+	upward := true
+
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		rows := make([]int, m.size)
+		for i := range rows {
+			rows[i] = i
+		}
+		if upward {
+			reverse(rows)
+		}
+		for _, row := range rows {
+			for _, col := range []int{right, right - 1} {
+				if m.isFunction[row][col] {
+					continue
+				}
+				m.modules[row][col] = bitAt(data, bitIndex)
+				bitIndex++
+			}
+		}
+		upward = !upward
+	}
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
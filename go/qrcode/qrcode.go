@@ -0,0 +1,124 @@
+// Package qrcode is a self-contained QR Code encoder (byte mode, error
+// correction level M, versions 1-10) with no CGO or third-party
+// dependencies. It implements just enough of ISO/IEC 18004 to turn a
+// short URL into a scannable code: bit-stream construction, Reed-Solomon
+// error correction, module placement, and mask selection.
+package qrcode
+
+import "errors"
+
+var errTooLong = errors.New("qrcode: data too long for the supported versions (up to V10, level M)")
+
+// Code is a generated QR symbol as a grid of modules. Modules[row][col]
+// is true for a dark module, false for a light one.
+type Code struct {
+	Version int
+	Size    int
+	Modules [][]bool
+}
+
+// Encode builds a QR code for data using byte mode at error correction
+// level M, auto-selecting the smallest version (1-10) that fits.
+func Encode(data []byte) (*Code, error) {
+	version, err := selectVersion(len(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bits := buildBitStream(data, version)
+	blocks, ecBlocks := splitAndCorrect(bits, version)
+	codewords := interleave(blocks, ecBlocks, version)
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+	mask := m.chooseBestMask()
+	m.applyMask(mask)
+	m.drawFormatInfo(mask)
+	m.drawVersionInfo()
+
+	return &Code{Version: version.version, Size: m.size, Modules: m.modules}, nil
+}
+
+// buildBitStream assembles the mode indicator, character count, payload,
+// terminator, bit padding, and byte padding into the raw data codewords
+// for one QR symbol (before splitting into RS blocks).
+func buildBitStream(data []byte, v versionSpec) []byte {
+	var bits bitWriter
+
+	bits.writeBits(0b0100, 4) // byte mode indicator
+	bits.writeBits(uint32(len(data)), v.charCountBits())
+	for _, b := range data {
+		bits.writeBits(uint32(b), 8)
+	}
+
+	capacityBits := v.dataCodewords() * 8
+
+	// Terminator: up to 4 zero bits, but never past the data capacity.
+	if remaining := capacityBits - bits.len(); remaining > 0 {
+		bits.writeBits(0, min(4, remaining))
+	}
+
+	// Pad to a byte boundary.
+	bits.padToByte()
+
+	// Pad with the alternating 0xEC/0x11 filler bytes until full.
+	filler := [2]byte{0xEC, 0x11}
+	for i := 0; bits.len() < capacityBits; i++ {
+		bits.writeBits(uint32(filler[i%2]), 8)
+	}
+
+	return bits.bytes
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// splitAndCorrect divides the raw data codewords across the version's RS
+// blocks and computes the EC codewords for each block.
+func splitAndCorrect(data []byte, v versionSpec) (dataBlocks, ecBlocks [][]byte) {
+	offset := 0
+	for _, g := range v.groups {
+		for i := 0; i < g.count; i++ {
+			block := data[offset : offset+g.dataPerBlock]
+			offset += g.dataPerBlock
+			dataBlocks = append(dataBlocks, block)
+			ecBlocks = append(ecBlocks, rsEncode(block, v.ecPerBlock))
+		}
+	}
+	return dataBlocks, ecBlocks
+}
+
+// interleave orders data and EC codewords per the QR standard: read one
+// codeword from each block in turn (data blocks first, then EC blocks).
+// The version's trailing remainder bits are not appended here: they are
+// all zero, and drawCodewords leaves those modules light because bitAt
+// reads past the end of the slice as 0.
+func interleave(dataBlocks, ecBlocks [][]byte, v versionSpec) []byte {
+	var out []byte
+
+	maxData := 0
+	for _, b := range dataBlocks {
+		if len(b) > maxData {
+			maxData = len(b)
+		}
+	}
+	for i := 0; i < maxData; i++ {
+		for _, b := range dataBlocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, b := range ecBlocks {
+			out = append(out, b[i])
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,134 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSelectVersionGrowsWithLength(t *testing.T) {
+	v1, err := selectVersion(10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v1.version != 1 {
+		t.Errorf("expected version 1 for 10 bytes, got %d", v1.version)
+	}
+
+	v2, err := selectVersion(200)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v2.version <= v1.version {
+		t.Errorf("expected a larger version for more data, got %d", v2.version)
+	}
+}
+
+func TestSelectVersionTooLong(t *testing.T) {
+	if _, err := selectVersion(10000); err == nil {
+		t.Error("expected an error for data exceeding V10 capacity")
+	}
+}
+
+func TestEncodeProducesSquareModuleGrid(t *testing.T) {
+	code, err := Encode([]byte("https://url-shortener.vibtellect.de/s/abc123"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(code.Modules) != code.Size {
+		t.Fatalf("expected %d rows, got %d", code.Size, len(code.Modules))
+	}
+	for i, row := range code.Modules {
+		if len(row) != code.Size {
+			t.Fatalf("row %d: expected %d columns, got %d", i, code.Size, len(row))
+		}
+	}
+
+	// The three finder patterns' centers must always be dark.
+	centers := [][2]int{{3, 3}, {3, code.Size - 4}, {code.Size - 4, 3}}
+	for _, c := range centers {
+		if !code.Modules[c[0]][c[1]] {
+			t.Errorf("expected finder center at %v to be dark", c)
+		}
+	}
+}
+
+func TestEncodeRejectsOversizedInput(t *testing.T) {
+	if _, err := Encode(bytes.Repeat([]byte("a"), 10000)); err == nil {
+		t.Error("expected an error for oversized input")
+	}
+}
+
+func TestPNGDecodesToExpectedDimensions(t *testing.T) {
+	code, err := Encode([]byte("https://short.example/s/abc123"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := code.PNG(256)
+	if err != nil {
+		t.Fatalf("PNG encode failed: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to decode generated PNG: %v", err)
+	}
+
+	bounds := img.Bounds()
+	if bounds.Dx() != bounds.Dy() {
+		t.Errorf("expected a square image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	if bounds.Dx() < code.Size {
+		t.Errorf("expected image at least %d px, got %d", code.Size, bounds.Dx())
+	}
+
+	// A valid QR render is strictly black and white.
+	seen := map[uint32]bool{}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			seen[r] = true
+		}
+	}
+	if len(seen) > 2 {
+		t.Errorf("expected at most 2 distinct colors in a QR render, got %d", len(seen))
+	}
+}
+
+func TestSVGContainsExpectedStructure(t *testing.T) {
+	code, err := Encode([]byte("https://short.example/s/abc123"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	svg := code.SVG(256)
+	if !strings.HasPrefix(svg, "<svg ") {
+		t.Error("expected the output to start with an <svg> tag")
+	}
+	if !strings.Contains(svg, `viewBox="0 0 256 256"`) {
+		t.Errorf("expected a 256x256 viewBox, got: %s", svg[:80])
+	}
+	if !strings.HasSuffix(svg, "</svg>") {
+		t.Error("expected the output to be a closed <svg> document")
+	}
+}
+
+func TestRSEncodeLength(t *testing.T) {
+	data := []byte{1, 2, 3, 4, 5}
+	ec := rsEncode(data, 10)
+	if len(ec) != 10 {
+		t.Errorf("expected 10 EC codewords, got %d", len(ec))
+	}
+}
+
+func TestGFMulIdentities(t *testing.T) {
+	if gfMul(0, 5) != 0 {
+		t.Error("multiplying by zero should yield zero")
+	}
+	if gfMul(1, 42) != 42 {
+		t.Error("multiplying by one should be the identity")
+	}
+}
@@ -0,0 +1,77 @@
+package qrcode
+
+// GF(256) arithmetic over the QR code's field, generated with the
+// standard primitive polynomial x^8 + x^4 + x^3 + x^2 + 1 (0x11d). These
+// tables back the Reed-Solomon error correction codewords required by
+// every QR symbol.
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+	for i := 255; i < 512; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsGeneratorPoly returns the coefficients (highest degree first, monic)
+// of the Reed-Solomon generator polynomial for the given number of error
+// correction codewords.
+func rsGeneratorPoly(ecCount int) []byte {
+	poly := []byte{1}
+	for i := 0; i < ecCount; i++ {
+		next := make([]byte, len(poly)+1)
+		for j, coef := range poly {
+			next[j] ^= gfMul(coef, gfExp[i])
+			next[j+1] ^= coef
+		}
+		poly = next
+	}
+
+	// The multiplication loop above builds poly lowest-degree-first
+	// (poly[0] is the constant term); reverse it so poly[0] is the
+	// leading monic coefficient, matching this function's contract and
+	// what rsEncode's long division expects.
+	for l, r := 0, len(poly)-1; l < r; l, r = l+1, r-1 {
+		poly[l], poly[r] = poly[r], poly[l]
+	}
+	return poly
+}
+
+// rsEncode computes the ecCount error correction codewords for a block
+// of data codewords via polynomial long division in GF(256).
+func rsEncode(data []byte, ecCount int) []byte {
+	generator := rsGeneratorPoly(ecCount)
+
+	remainder := make([]byte, len(data)+ecCount)
+	copy(remainder, data)
+
+	for i := 0; i < len(data); i++ {
+		coef := remainder[i]
+		if coef == 0 {
+			continue
+		}
+		for j, g := range generator {
+			remainder[i+j] ^= gfMul(g, coef)
+		}
+	}
+
+	return remainder[len(data):]
+}
@@ -0,0 +1,33 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SVG renders the code as a minimal SVG document: one <rect> per dark
+// module plus a white background, sized targetSize CSS pixels square
+// (quiet zone included).
+func (c *Code) SVG(targetSize int) string {
+	dimension := c.Size + 2*quietZone
+	unit := float64(targetSize) / float64(dimension)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d">`,
+		targetSize, targetSize, targetSize, targetSize)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#ffffff"/>`, targetSize, targetSize)
+
+	for row := 0; row < c.Size; row++ {
+		for col := 0; col < c.Size; col++ {
+			if !c.Modules[row][col] {
+				continue
+			}
+			x := (float64(col) + quietZone) * unit
+			y := (float64(row) + quietZone) * unit
+			fmt.Fprintf(&b, `<rect x="%.3f" y="%.3f" width="%.3f" height="%.3f" fill="#000000"/>`, x, y, unit, unit)
+		}
+	}
+
+	b.WriteString(`</svg>`)
+	return b.String()
+}
@@ -0,0 +1,34 @@
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// dynamoDBAdapter adapts *dynamodb.Client to UpdateItemAPI so production
+// code can build a Limiter without the rest of the package depending
+// directly on the AWS SDK client type.
+type dynamoDBAdapter struct {
+	client *dynamodb.Client
+}
+
+// Adapt wraps a real DynamoDB client for use as a Limiter's Client.
+func Adapt(client *dynamodb.Client) UpdateItemAPI {
+	return dynamoDBAdapter{client: client}
+}
+
+func (a dynamoDBAdapter) UpdateItem(ctx context.Context, params *UpdateItemInput) (*UpdateItemOutput, error) {
+	out, err := a.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(params.TableName),
+		Key:                       params.Key,
+		UpdateExpression:          aws.String(params.UpdateExpression),
+		ExpressionAttributeValues: params.ExpressionAttributeValues,
+		ReturnValues:              "UPDATED_NEW",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &UpdateItemOutput{Attributes: out.Attributes}, nil
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Blocklist is a set of malicious domains loaded once at cold start from a
+// newline-delimited S3 object. Lookups also match subdomains of a blocked
+// domain (e.g. "evil.com" blocks "sub.evil.com").
+type Blocklist struct {
+	domains map[string]struct{}
+}
+
+// NewBlocklist builds a Blocklist from pre-loaded domain names, lowercased
+// and with surrounding whitespace trimmed.
+func NewBlocklist(domains []string) *Blocklist {
+	set := make(map[string]struct{}, len(domains))
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || strings.HasPrefix(d, "#") {
+			continue
+		}
+		set[d] = struct{}{}
+	}
+	return &Blocklist{domains: set}
+}
+
+// LoadBlocklistFromS3 fetches a newline-delimited domain list from bucket/key.
+// Blank lines and lines starting with "#" are ignored, matching the style
+// of a hand-maintained blocklist file.
+func LoadBlocklistFromS3(ctx context.Context, client *s3.Client, bucket, key string) (*Blocklist, error) {
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit: fetch blocklist s3://%s/%s: %w", bucket, key, err)
+	}
+	defer out.Body.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(out.Body)
+	for scanner.Scan() {
+		domains = append(domains, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ratelimit: read blocklist body: %w", err)
+	}
+
+	return NewBlocklist(domains), nil
+}
+
+// Blocks reports whether host (or any parent domain of host) is blocked.
+func (b *Blocklist) Blocks(host string) bool {
+	if b == nil || len(b.domains) == 0 {
+		return false
+	}
+
+	host = strings.ToLower(host)
+	for {
+		if _, blocked := b.domains[host]; blocked {
+			return true
+		}
+		dot := strings.IndexByte(host, '.')
+		if dot == -1 {
+			return false
+		}
+		host = host[dot+1:]
+	}
+}
@@ -0,0 +1,124 @@
+// Package ratelimit implements a DynamoDB-backed fixed-window rate limiter.
+// Counters are stored as ordinary table items keyed by caller and minute, so
+// the limit is enforced consistently across concurrent Lambda instances
+// without a separate in-memory store.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// UpdateItemAPI is the subset of *dynamodb.Client the limiter needs. Tests
+// inject a fake implementation to exercise window rollover without a real
+// DynamoDB table. See Adapt for wiring a real *dynamodb.Client.
+type UpdateItemAPI interface {
+	UpdateItem(ctx context.Context, params *UpdateItemInput) (*UpdateItemOutput, error)
+}
+
+// UpdateItemInput/UpdateItemOutput mirror the shape of the single DynamoDB
+// call the limiter makes.
+type UpdateItemInput struct {
+	TableName                 string
+	Key                       map[string]dynamodbtypes.AttributeValue
+	UpdateExpression          string
+	ExpressionAttributeValues map[string]dynamodbtypes.AttributeValue
+}
+
+type UpdateItemOutput struct {
+	Attributes map[string]dynamodbtypes.AttributeValue
+}
+
+// windowTTL is how long after its window starts a counter item expires
+// (one minute past the end of the one-minute window), stored as a DynamoDB
+// TTL attribute, so the table doesn't accumulate stale windows.
+const windowTTL = 2 * time.Minute
+
+// Limiter enforces a fixed-window request limit per key (e.g. an IP address
+// or an owner subject).
+type Limiter struct {
+	Client    UpdateItemAPI
+	TableName string
+	// Now is overridable for tests; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Decision is the outcome of a single Allow check.
+type Decision struct {
+	Allowed      bool
+	Remaining    int
+	RetryAfter   time.Duration
+	CurrentCount int64
+	WindowLimit  int64
+}
+
+// Allow increments the counter for key's current one-minute window and
+// reports whether the caller is still within limit.
+func (l *Limiter) Allow(ctx context.Context, key string, limit int64) (Decision, error) {
+	now := l.now()
+	windowStart := now.Truncate(time.Minute)
+	epochMinute := windowStart.Unix() / 60
+
+	pk := fmt.Sprintf("rl#%s#%d", key, epochMinute)
+	expiresAt := windowStart.Add(windowTTL).Unix()
+
+	out, err := l.Client.UpdateItem(ctx, &UpdateItemInput{
+		TableName: l.TableName,
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: pk},
+		},
+		UpdateExpression: "ADD counter :incr SET expires_at = :ttl",
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":incr": &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+			":ttl":  &dynamodbtypes.AttributeValueMemberN{Value: fmt.Sprintf("%d", expiresAt)},
+		},
+	})
+	if err != nil {
+		return Decision{}, fmt.Errorf("ratelimit: update counter: %w", err)
+	}
+
+	count, err := counterFromAttributes(out.Attributes)
+	if err != nil {
+		return Decision{}, fmt.Errorf("ratelimit: %w", err)
+	}
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	retryAfter := windowStart.Add(time.Minute).Sub(now)
+	if retryAfter < 0 {
+		retryAfter = 0
+	}
+
+	return Decision{
+		Allowed:      count <= limit,
+		Remaining:    int(remaining),
+		RetryAfter:   retryAfter,
+		CurrentCount: count,
+		WindowLimit:  limit,
+	}, nil
+}
+
+func (l *Limiter) now() time.Time {
+	if l.Now != nil {
+		return l.Now()
+	}
+	return time.Now()
+}
+
+func counterFromAttributes(attrs map[string]dynamodbtypes.AttributeValue) (int64, error) {
+	attr, ok := attrs["counter"].(*dynamodbtypes.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("counter attribute missing or wrong type")
+	}
+	var count int64
+	if _, err := fmt.Sscanf(attr.Value, "%d", &count); err != nil {
+		return 0, fmt.Errorf("parse counter value %q: %w", attr.Value, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,123 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeUpdateItemClient is an in-memory stand-in for DynamoDB's UpdateItem
+// that accumulates counters by partition key, mirroring the real table's
+// ADD semantics closely enough to exercise window rollover.
+type fakeUpdateItemClient struct {
+	counters map[string]int64
+	calls    int
+}
+
+func newFakeUpdateItemClient() *fakeUpdateItemClient {
+	return &fakeUpdateItemClient{counters: make(map[string]int64)}
+}
+
+func (f *fakeUpdateItemClient) UpdateItem(ctx context.Context, params *UpdateItemInput) (*UpdateItemOutput, error) {
+	f.calls++
+	pk := params.Key["short_code"].(*dynamodbtypes.AttributeValueMemberS).Value
+	f.counters[pk]++
+
+	return &UpdateItemOutput{
+		Attributes: map[string]dynamodbtypes.AttributeValue{
+			"counter": &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(f.counters[pk], 10)},
+		},
+	}, nil
+}
+
+func TestLimiterAllow(t *testing.T) {
+	client := newFakeUpdateItemClient()
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	current := base
+
+	limiter := &Limiter{
+		Client:    client,
+		TableName: "test-table",
+		Now:       func() time.Time { return current },
+	}
+
+	// Within the same window, requests 1-3 are allowed at limit 3, the 4th is not.
+	for i := 1; i <= 3; i++ {
+		decision, err := limiter.Allow(context.Background(), "1.2.3.4", 3)
+		if err != nil {
+			t.Fatalf("unexpected error on request %d: %v", i, err)
+		}
+		if !decision.Allowed {
+			t.Errorf("request %d: expected allowed, got denied (count=%d)", i, decision.CurrentCount)
+		}
+	}
+
+	decision, err := limiter.Allow(context.Background(), "1.2.3.4", 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decision.Allowed {
+		t.Errorf("4th request within the window should be denied, got allowed (count=%d)", decision.CurrentCount)
+	}
+	if decision.Remaining != 0 {
+		t.Errorf("expected 0 remaining once over limit, got %d", decision.Remaining)
+	}
+
+	// Rolling into the next minute resets the window.
+	current = base.Add(time.Minute)
+	decision, err = limiter.Allow(context.Background(), "1.2.3.4", 3)
+	if err != nil {
+		t.Fatalf("unexpected error after rollover: %v", err)
+	}
+	if !decision.Allowed {
+		t.Errorf("first request in new window should be allowed, got denied (count=%d)", decision.CurrentCount)
+	}
+}
+
+func TestLimiterAllowDifferentKeysIndependent(t *testing.T) {
+	client := newFakeUpdateItemClient()
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := &Limiter{Client: client, TableName: "test-table", Now: func() time.Time { return now }}
+
+	if _, err := limiter.Allow(context.Background(), "1.1.1.1", 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decision, err := limiter.Allow(context.Background(), "2.2.2.2", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decision.Allowed {
+		t.Error("a different key should have its own independent counter")
+	}
+}
+
+func TestBlocklistBlocks(t *testing.T) {
+	bl := NewBlocklist([]string{"evil.com", "# a comment", "", "  spammy.net  "})
+
+	cases := []struct {
+		host    string
+		blocked bool
+	}{
+		{"evil.com", true},
+		{"sub.evil.com", true},
+		{"spammy.net", true},
+		{"example.com", false},
+		{"notevilcom.com", false},
+	}
+
+	for _, c := range cases {
+		if got := bl.Blocks(c.host); got != c.blocked {
+			t.Errorf("Blocks(%q) = %v, want %v", c.host, got, c.blocked)
+		}
+	}
+}
+
+func TestBlocklistNilIsSafe(t *testing.T) {
+	var bl *Blocklist
+	if bl.Blocks("anything.com") {
+		t.Error("nil blocklist should never block")
+	}
+}
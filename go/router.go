@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// rawEvent is probed to tell which Lambda trigger invoked the function:
+// API Gateway HTTP API requests carry a "routeKey", DynamoDB Streams
+// batches carry "Records".
+type rawEvent struct {
+	RouteKey string          `json:"routeKey"`
+	Records  json.RawMessage `json:"Records"`
+}
+
+// route dispatches a raw Lambda payload to the HTTP handler or the
+// DynamoDB Streams handler depending on which trigger invoked it, so a
+// single function can serve both the public API and stream-driven
+// bookkeeping (e.g. decrementing active_urls when a TTL deletes a row).
+func route(ctx context.Context, payload json.RawMessage) (interface{}, error) {
+	var probe rawEvent
+	if err := json.Unmarshal(payload, &probe); err != nil {
+		return nil, err
+	}
+
+	if probe.Records != nil {
+		var evt events.DynamoDBEvent
+		if err := json.Unmarshal(payload, &evt); err != nil {
+			return nil, err
+		}
+		return nil, handleStreamEvent(ctx, evt)
+	}
+
+	var request events.APIGatewayV2HTTPRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, err
+	}
+	return handleRequest(ctx, request)
+}
+
+// handleStreamEvent keeps the active_urls counter in sync with rows that
+// disappear from the table. Only TTL-driven deletes are counted: the API
+// never deletes items itself, and any other delete (e.g. a manual one) is
+// ignored, so it only reacts to the TTL's own principal. The table also
+// holds non-URL items; rate-limit window counters expire via the same TTL
+// attribute, so the deleted item's image is checked to make sure it was
+// actually a URLRecord before decrementing.
+func handleStreamEvent(ctx context.Context, evt events.DynamoDBEvent) error {
+	for _, record := range evt.Records {
+		if record.EventName != "REMOVE" {
+			continue
+		}
+		if record.UserIdentity == nil || record.UserIdentity.PrincipalID != "dynamodb.amazonaws.com" {
+			continue
+		}
+		if !wasURLRecord(record.Change.OldImage) {
+			continue
+		}
+		if err := incrementStat(ctx, statsActiveKey, -1); err != nil {
+			log.Printf("Failed to decrement active_urls counter: %v", err)
+		}
+	}
+	return nil
+}
+
+// wasURLRecord reports whether a deleted item's before-image belongs to a
+// URLRecord, as opposed to a rate-limit window counter or the short code
+// counter, which share the same table.
+func wasURLRecord(oldImage map[string]events.DynamoDBAttributeValue) bool {
+	_, ok := oldImage["original_url"]
+	return ok
+}
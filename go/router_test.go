@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+func TestWasURLRecord(t *testing.T) {
+	cases := []struct {
+		name     string
+		oldImage map[string]events.DynamoDBAttributeValue
+		want     bool
+	}{
+		{
+			name: "url record",
+			oldImage: map[string]events.DynamoDBAttributeValue{
+				"short_code":   events.NewStringAttribute("abc123"),
+				"original_url": events.NewStringAttribute("https://example.com"),
+			},
+			want: true,
+		},
+		{
+			name: "rate limit counter",
+			oldImage: map[string]events.DynamoDBAttributeValue{
+				"short_code": events.NewStringAttribute("rl#create#ip#1.2.3.4#28284123"),
+				"counter":    events.NewNumberAttribute("5"),
+			},
+			want: false,
+		},
+		{
+			name:     "empty image",
+			oldImage: map[string]events.DynamoDBAttributeValue{},
+			want:     false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := wasURLRecord(c.oldImage); got != c.want {
+				t.Errorf("wasURLRecord(%v) = %v, want %v", c.oldImage, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// marshalURLRecord converts a URLRecord to a DynamoDB item map.
+func marshalURLRecord(record URLRecord) (map[string]dynamodbtypes.AttributeValue, error) {
+	return attributevalue.MarshalMap(record)
+}
+
+// shortCodeStrategy selects how POST /create derives a code for a new URLRecord.
+type shortCodeStrategy string
+
+const (
+	strategyHash    shortCodeStrategy = "hash"
+	strategyCounter shortCodeStrategy = "counter"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	counterItemKey = "__counter__"
+	minCodeLength  = 6
+	maxPutAttempts = 3
+)
+
+var customAliasPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{3,32}$`)
+
+// shortCodeAPI is the subset of *dynamodb.Client the allocator needs. Tests
+// inject a fake implementation to exercise the atomic-counter-and-retry path
+// without a real DynamoDB table, the same seam ratelimit.UpdateItemAPI uses
+// for the rate limiter.
+type shortCodeAPI interface {
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+}
+
+// shortCodeStrategyFromEnv reads SHORTCODE_STRATEGY, defaulting to the
+// hash-based strategy that was the only behavior before counter mode existed.
+func shortCodeStrategyFromEnv() shortCodeStrategy {
+	switch shortCodeStrategy(os.Getenv("SHORTCODE_STRATEGY")) {
+	case strategyCounter:
+		return strategyCounter
+	default:
+		return strategyHash
+	}
+}
+
+// generateShortCode is the deterministic hash strategy: SHA-256 of the
+// input URL, truncated to 8 hex characters. Kept for backward compatibility
+// with short codes minted before the counter strategy existed.
+func generateShortCode(input string) string {
+	hash := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(hash[:])[:8]
+}
+
+// validateCustomAlias reports whether alias is an acceptable "custom" value
+// from the create request body.
+// Reserved keys used internally (like the counter item) must never be
+// claimable through the public API, regardless of character class.
+func validateCustomAlias(alias string) bool {
+	if alias == counterItemKey {
+		return false
+	}
+	return customAliasPattern.MatchString(alias)
+}
+
+// encodeBase62 encodes n as Base62 using [0-9A-Za-z], left-padded with the
+// alphabet's zero digit to at least minLen characters.
+func encodeBase62(n int64, minLen int) string {
+	if n == 0 {
+		return padBase62("0", minLen)
+	}
+
+	var buf []byte
+	for n > 0 {
+		buf = append([]byte{base62Alphabet[n%62]}, buf...)
+		n /= 62
+	}
+	return padBase62(string(buf), minLen)
+}
+
+func padBase62(s string, minLen int) string {
+	for len(s) < minLen {
+		s = string(base62Alphabet[0]) + s
+	}
+	return s
+}
+
+// nextCounterValue atomically increments the counter item and returns the
+// new value, creating the item on first use.
+func nextCounterValue(ctx context.Context, store shortCodeAPI) (int64, error) {
+	out, err := store.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: counterItemKey},
+		},
+		UpdateExpression: aws.String("ADD counter :incr"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":incr": &dynamodbtypes.AttributeValueMemberN{Value: "1"},
+		},
+		ReturnValues: dynamodbtypes.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("update counter: %w", err)
+	}
+
+	counterAttr, ok := out.Attributes["counter"].(*dynamodbtypes.AttributeValueMemberN)
+	if !ok {
+		return 0, fmt.Errorf("counter attribute missing or wrong type")
+	}
+
+	var value int64
+	if _, err := fmt.Sscanf(counterAttr.Value, "%d", &value); err != nil {
+		return 0, fmt.Errorf("parse counter value %q: %w", counterAttr.Value, err)
+	}
+	return value, nil
+}
+
+// putIfAbsent writes item with a conditional expression so an existing
+// short_code is never overwritten. It reports whether the put succeeded.
+func putIfAbsent(ctx context.Context, store shortCodeAPI, item map[string]dynamodbtypes.AttributeValue) (bool, error) {
+	_, err := store.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(short_code)"),
+	})
+	if err != nil {
+		var condFailed *dynamodbtypes.ConditionalCheckFailedException
+		if errors.As(err, &condFailed) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// generateCounterShortCode allocates the next monotonic counter value and
+// encodes it as Base62. It does not retry; allocateShortCode retries
+// forward on write conflicts.
+func generateCounterShortCode(ctx context.Context, store shortCodeAPI) (string, error) {
+	n, err := nextCounterValue(ctx, store)
+	if err != nil {
+		return "", err
+	}
+	return encodeBase62(n, minCodeLength), nil
+}
+
+// reserveShortCode builds a URLRecord item for candidate and attempts a
+// conditional put, returning ok=false if the code is already taken.
+func reserveShortCode(ctx context.Context, store shortCodeAPI, candidate string, record URLRecord) (bool, error) {
+	record.ShortCode = candidate
+	item, err := marshalURLRecord(record)
+	if err != nil {
+		return false, err
+	}
+	return putIfAbsent(ctx, store, item)
+}
+
+// allocateShortCode runs the configured strategy end-to-end, retrying the
+// counter strategy a bounded number of times if a collision is detected.
+func allocateShortCode(ctx context.Context, store shortCodeAPI, strategy shortCodeStrategy, record URLRecord) (string, error) {
+	if strategy == strategyHash {
+		candidate := generateShortCode(record.OriginalURL)
+		ok, err := reserveShortCode(ctx, store, candidate, record)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return candidate, nil
+		}
+
+		// The hash code is a pure function of the URL, so shortening the
+		// same URL twice is a routine, not exceptional, case: return the
+		// existing short code instead of failing the request. A candidate
+		// occupied by a different URL is a genuine (astronomically rare)
+		// hash collision and still errors out.
+		existing, found, err := getURLRecord(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if found && existing.OriginalURL == record.OriginalURL {
+			return candidate, nil
+		}
+		return "", fmt.Errorf("short code %q already exists", candidate)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxPutAttempts; attempt++ {
+		candidate, err := generateCounterShortCode(ctx, store)
+		if err != nil {
+			return "", err
+		}
+		ok, err := reserveShortCode(ctx, store, candidate, record)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return candidate, nil
+		}
+		lastErr = fmt.Errorf("short code %q already exists", candidate)
+		log.Printf("short code collision on attempt %d: %v", attempt+1, lastErr)
+	}
+	return "", fmt.Errorf("failed to allocate short code after %d attempts: %w", maxPutAttempts, lastErr)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// fakeShortCodeStore is an in-memory stand-in for the *dynamodb.Client
+// methods shortCodeAPI needs, mirroring ratelimit's fakeUpdateItemClient:
+// UpdateItem accumulates the counter item, PutItem enforces
+// attribute_not_exists(short_code) against an in-memory item set.
+type fakeShortCodeStore struct {
+	counter     int64
+	taken       map[string]bool
+	updateCalls int
+	putCalls    int
+}
+
+func newFakeShortCodeStore() *fakeShortCodeStore {
+	return &fakeShortCodeStore{taken: make(map[string]bool)}
+}
+
+func (f *fakeShortCodeStore) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.updateCalls++
+	f.counter++
+	return &dynamodb.UpdateItemOutput{
+		Attributes: map[string]dynamodbtypes.AttributeValue{
+			"counter": &dynamodbtypes.AttributeValueMemberN{Value: strconv.FormatInt(f.counter, 10)},
+		},
+	}, nil
+}
+
+func (f *fakeShortCodeStore) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.putCalls++
+	code := params.Item["short_code"].(*dynamodbtypes.AttributeValueMemberS).Value
+	if f.taken[code] {
+		return nil, &dynamodbtypes.ConditionalCheckFailedException{}
+	}
+	f.taken[code] = true
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// Test Base62 encoding
+func TestEncodeBase62(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		desc     string
+		input    int64
+		minLen   int
+		expected string
+	}{
+		{"zero padded", 0, 6, "000000"},
+		{"small value padded", 61, 6, "00000z"},
+		{"sixty-two rolls over", 62, 6, "000010"},
+		{"no padding needed", 99999999999, 6, "1l9Zo9n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			got := encodeBase62(c.input, c.minLen)
+			if got != c.expected {
+				t.Errorf("encodeBase62(%d, %d) = %s, want %s", c.input, c.minLen, got, c.expected)
+			}
+		})
+	}
+}
+
+// Test custom alias validation
+func TestValidateCustomAlias(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		desc  string
+		alias string
+		valid bool
+	}{
+		{"valid alphanumeric", "my-alias_1", true},
+		{"too short", "ab", false},
+		{"too long", "a123456789012345678901234567890123", false},
+		{"invalid chars", "my alias!", false},
+		{"empty", "", false},
+		{"reserved counter key", counterItemKey, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			got := validateCustomAlias(c.alias)
+			if got != c.valid {
+				t.Errorf("validateCustomAlias(%q) = %v, want %v", c.alias, got, c.valid)
+			}
+		})
+	}
+}
+
+// Test strategy selection from env, parallel to TestGenerateShortCode's
+// coverage of the underlying hash function.
+func TestShortCodeStrategyFromEnv(t *testing.T) {
+	old := os.Getenv("SHORTCODE_STRATEGY")
+	defer os.Setenv("SHORTCODE_STRATEGY", old)
+
+	cases := []struct {
+		desc     string
+		envValue string
+		expected shortCodeStrategy
+	}{
+		{"unset defaults to hash", "", strategyHash},
+		{"explicit hash", "hash", strategyHash},
+		{"explicit counter", "counter", strategyCounter},
+		{"unknown falls back to hash", "bogus", strategyHash},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			os.Setenv("SHORTCODE_STRATEGY", c.envValue)
+			got := shortCodeStrategyFromEnv()
+			if got != c.expected {
+				t.Errorf("shortCodeStrategyFromEnv() with %q = %v, want %v", c.envValue, got, c.expected)
+			}
+		})
+	}
+}
+
+// Test the counter-strategy allocation path, parallel to
+// TestGenerateShortCode's coverage of the hash strategy. This test covers
+// the atomic increment; the tests below cover the conditional put and the
+// bounded collision retry.
+func TestNextCounterValue(t *testing.T) {
+	store := newFakeShortCodeStore()
+	ctx := context.Background()
+
+	for i, want := range []int64{1, 2, 3} {
+		got, err := nextCounterValue(ctx, store)
+		if err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("call %d: nextCounterValue() = %d, want %d", i, got, want)
+		}
+	}
+	if store.updateCalls != 3 {
+		t.Errorf("expected 3 UpdateItem calls, got %d", store.updateCalls)
+	}
+}
+
+func TestPutIfAbsent(t *testing.T) {
+	store := newFakeShortCodeStore()
+	ctx := context.Background()
+	item := map[string]dynamodbtypes.AttributeValue{
+		"short_code": &dynamodbtypes.AttributeValueMemberS{Value: "abc123"},
+	}
+
+	ok, err := putIfAbsent(ctx, store, item)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("expected first put to succeed")
+	}
+
+	ok, err = putIfAbsent(ctx, store, item)
+	if err != nil {
+		t.Fatalf("unexpected error on duplicate put: %v", err)
+	}
+	if ok {
+		t.Error("expected second put of the same short_code to fail")
+	}
+}
+
+func TestReserveShortCode(t *testing.T) {
+	store := newFakeShortCodeStore()
+	ctx := context.Background()
+	record := URLRecord{OriginalURL: "https://example.com"}
+
+	ok, err := reserveShortCode(ctx, store, "abc123", record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Error("expected reservation to succeed")
+	}
+
+	ok, err = reserveShortCode(ctx, store, "abc123", record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("expected reservation of an already-taken code to fail")
+	}
+}
+
+func TestAllocateShortCodeCounterRetriesOnCollision(t *testing.T) {
+	store := newFakeShortCodeStore()
+	ctx := context.Background()
+	record := URLRecord{OriginalURL: "https://example.com"}
+
+	// Pre-occupy the first two counter values the allocator will derive so
+	// it has to retry forward before landing on a free code.
+	store.taken[encodeBase62(1, minCodeLength)] = true
+	store.taken[encodeBase62(2, minCodeLength)] = true
+
+	code, err := allocateShortCode(ctx, store, strategyCounter, record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := encodeBase62(3, minCodeLength)
+	if code != want {
+		t.Errorf("allocateShortCode() = %q, want %q", code, want)
+	}
+	if store.putCalls != 3 {
+		t.Errorf("expected 3 PutItem attempts, got %d", store.putCalls)
+	}
+}
+
+func TestAllocateShortCodeCounterExhaustsRetries(t *testing.T) {
+	store := newFakeShortCodeStore()
+	ctx := context.Background()
+	record := URLRecord{OriginalURL: "https://example.com"}
+
+	// Occupy every candidate the bounded retry loop could possibly try.
+	for n := int64(1); n <= maxPutAttempts; n++ {
+		store.taken[encodeBase62(n, minCodeLength)] = true
+	}
+
+	_, err := allocateShortCode(ctx, store, strategyCounter, record)
+	if err == nil {
+		t.Fatal("expected an error once all retries collide")
+	}
+	if store.putCalls != maxPutAttempts {
+		t.Errorf("expected %d PutItem attempts, got %d", maxPutAttempts, store.putCalls)
+	}
+}
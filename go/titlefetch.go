@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	pageMetaFetchTimeout = 2 * time.Second
+	pageMetaMaxBodyBytes = 64 * 1024
+)
+
+var (
+	titleTagPattern        = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	descriptionMetaPattern = regexp.MustCompile(`(?is)<meta\s+[^>]*name=["']description["'][^>]*content=["']([^"']*)["'][^>]*>`)
+	htmlTagPattern         = regexp.MustCompile(`<[^>]*>`)
+)
+
+// pageMetaHTTPClient rejects any redirect hop that resolves to a
+// non-public address, so a target that redirects after the initial
+// isPublicHost check can't be used to bypass it.
+var pageMetaHTTPClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if err := checkPublicHost(req.URL.Hostname()); err != nil {
+			return fmt.Errorf("redirect to %s rejected: %w", req.URL, err)
+		}
+		return nil
+	},
+}
+
+// errNotPublicHost is wrapped by checkPublicHost's errors so callers that
+// only care about the class of failure can match on it with errors.Is.
+var errNotPublicHost = errors.New("not a public host")
+
+// checkPublicHost resolves host and rejects it if any resolved address is
+// loopback, link-local, private, unspecified, or multicast. This is the
+// fetchPageMeta SSRF guard: without it a caller-supplied URL lets the
+// server probe its own internal network or cloud metadata endpoint.
+func checkPublicHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		return checkPublicIP(ip)
+	}
+
+	addrs, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("resolve %s: %w", host, err)
+	}
+	for _, ip := range addrs {
+		if err := checkPublicIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkPublicIP(ip net.IP) error {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%s: %w", ip, errNotPublicHost)
+	}
+	return nil
+}
+
+// fetchPageMeta makes a bounded best-effort GET of target to cache its
+// page title and meta description at create time, so /preview doesn't
+// need to re-fetch the destination on every call. Any failure (timeout,
+// non-public host, non-2xx status, malformed HTML) yields empty strings
+// rather than failing the create request.
+func fetchPageMeta(target string) (title, description string) {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return "", ""
+	}
+	if err := checkPublicHost(parsed.Hostname()); err != nil {
+		log.Printf("Refusing to fetch page metadata for %s: %v", target, err)
+		return "", ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pageMetaFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", ""
+	}
+
+	resp, err := pageMetaHTTPClient.Do(req)
+	if err != nil {
+		log.Printf("Failed to fetch page metadata for %s: %v", target, err)
+		return "", ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", ""
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, pageMetaMaxBodyBytes))
+	if err != nil {
+		return "", ""
+	}
+
+	return extractPageMeta(body)
+}
+
+// extractPageMeta pulls the title and meta-description out of an HTML
+// document's raw bytes. Split out from fetchPageMeta so the extraction
+// logic can be tested without a network round trip.
+func extractPageMeta(body []byte) (title, description string) {
+	if m := titleTagPattern.FindSubmatch(body); m != nil {
+		title = cleanHTMLText(string(m[1]))
+	}
+	if m := descriptionMetaPattern.FindSubmatch(body); m != nil {
+		description = cleanHTMLText(string(m[1]))
+	}
+	return title, description
+}
+
+func cleanHTMLText(s string) string {
+	return htmlTagPattern.ReplaceAllString(s, "")
+}
+
+// updatePageMeta patches a URLRecord's title/description after the fact,
+// once the background fetch kicked off by handleCreate completes. It uses
+// an UpdateItem SET rather than a full PutItem so it only ever touches
+// these two attributes, regardless of what else has changed on the item
+// (e.g. click_count) since the record was created.
+func updatePageMeta(ctx context.Context, shortCode, title, description string) error {
+	_, err := dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableName),
+		Key: map[string]dynamodbtypes.AttributeValue{
+			"short_code": &dynamodbtypes.AttributeValueMemberS{Value: shortCode},
+		},
+		UpdateExpression: aws.String("SET title = :title, description = :description"),
+		ExpressionAttributeValues: map[string]dynamodbtypes.AttributeValue{
+			":title":       &dynamodbtypes.AttributeValueMemberS{Value: title},
+			":description": &dynamodbtypes.AttributeValueMemberS{Value: description},
+		},
+	})
+	return err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExtractPageMetaTitleAndDescription(t *testing.T) {
+	body := []byte(`<html><head>
+		<title>Example <b>Site</b></title>
+		<meta name="description" content="An example site for testing">
+	</head><body></body></html>`)
+
+	title, description := extractPageMeta(body)
+	if title != "Example Site" {
+		t.Errorf("expected title %q, got %q", "Example Site", title)
+	}
+	if description != "An example site for testing" {
+		t.Errorf("expected description %q, got %q", "An example site for testing", description)
+	}
+}
+
+func TestExtractPageMetaMissingTags(t *testing.T) {
+	title, description := extractPageMeta([]byte(`<html><body>no head here</body></html>`))
+	if title != "" || description != "" {
+		t.Errorf("expected empty title/description when tags are absent, got %q / %q", title, description)
+	}
+}
+
+func TestCleanHTMLText(t *testing.T) {
+	if got := cleanHTMLText("Hello <b>World</b>"); got != "Hello World" {
+		t.Errorf("expected tags to be stripped, got %q", got)
+	}
+}
+
+func TestCheckPublicHostRejectsNonPublicAddresses(t *testing.T) {
+	cases := []struct {
+		desc string
+		host string
+	}{
+		{"loopback", "127.0.0.1"},
+		{"loopback v6", "::1"},
+		{"link-local", "169.254.169.254"},
+		{"private RFC1918", "10.0.0.5"},
+		{"private RFC1918 172 range", "172.16.0.5"},
+		{"unspecified", "0.0.0.0"},
+		{"multicast", "224.0.0.1"},
+	}
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			if err := checkPublicHost(c.host); err == nil {
+				t.Errorf("checkPublicHost(%q) = nil, want an error", c.host)
+			}
+		})
+	}
+}
+
+func TestCheckPublicHostAllowsPublicAddresses(t *testing.T) {
+	cases := []string{"8.8.8.8", "1.1.1.1"}
+	for _, host := range cases {
+		t.Run(host, func(t *testing.T) {
+			if err := checkPublicHost(host); err != nil {
+				t.Errorf("checkPublicHost(%q) = %v, want nil", host, err)
+			}
+		})
+	}
+}
+
+// TestFetchPageMetaRejectsLoopbackTarget documents the SSRF guard at the
+// fetchPageMeta entry point: even though httptest servers are reachable,
+// fetchPageMeta must refuse to hit them since they resolve to loopback.
+func TestFetchPageMetaRejectsLoopbackTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><head><title>Should not be fetched</title></head></html>`))
+	}))
+	defer server.Close()
+
+	title, description := fetchPageMeta(server.URL)
+	if title != "" || description != "" {
+		t.Errorf("expected empty title/description for a loopback target, got %q / %q", title, description)
+	}
+}
@@ -0,0 +1,122 @@
+// Package useragent provides a minimal, dependency-free classifier for the
+// incoming User-Agent header on the redirect path. It is intentionally not
+// exhaustive: it covers the handful of bots, browsers, and platforms the
+// redirect handler needs to distinguish for metrics and click counting.
+package useragent
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Info is the result of classifying a User-Agent string.
+type Info struct {
+	Platform       string // "Desktop", "Mobile", or "Unknown"
+	OS             string // e.g. "Windows", "macOS", "Linux", "iOS", "Android"
+	Browser        string // e.g. "Chrome", "Firefox", "Safari", "Edge", "CLI App"
+	BrowserVersion string // e.g. "116.0.0.0"; empty if not detected
+	IsBot          bool
+}
+
+// cliOverrideMarker identifies the project's own CLI client. It is checked
+// before bot/browser detection, mirroring the Mattermost-style substring
+// override this was modeled on.
+const cliOverrideMarker = "URLShortenerCLI"
+
+var knownBots = []struct {
+	marker string
+	name   string
+}{
+	{"Googlebot", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"DuckDuckBot", "DuckDuckBot"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"python-requests", "python-requests"},
+}
+
+var browserPatterns = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	// Edge (Chromium-based) must be checked before Chrome/Safari since its
+	// UA string contains both "Chrome" and "Safari" tokens.
+	{"Edge", regexp.MustCompile(`Edg/([\d.]+)`)},
+	{"Chrome", regexp.MustCompile(`Chrome/([\d.]+)`)},
+	{"Firefox", regexp.MustCompile(`Firefox/([\d.]+)`)},
+	// Safari's own UA has no "Chrome" token; Version/x.y carries its version.
+	{"Safari", regexp.MustCompile(`Version/([\d.]+).*Safari/`)},
+}
+
+var osPatterns = []struct {
+	name   string
+	marker string
+}{
+	{"Windows", "Windows NT"},
+	// iOS markers must be checked before the bare "Mac OS X" marker: iOS
+	// Safari's UA contains "like Mac OS X", which would otherwise match
+	// macOS first.
+	{"iOS", "iPhone OS"},
+	{"iOS", "like Mac OS X"},
+	{"macOS", "Mac OS X"},
+	{"Android", "Android"},
+	{"Linux", "Linux"},
+}
+
+var mobileMarkers = []string{"Mobi", "iPhone", "Android"}
+
+// Parse classifies a raw User-Agent header value.
+func Parse(ua string) Info {
+	if strings.Contains(ua, cliOverrideMarker) {
+		return Info{Platform: "Unknown", OS: "Unknown", Browser: "CLI App"}
+	}
+
+	if name, ok := detectBot(ua); ok {
+		return Info{Platform: "Unknown", OS: detectOS(ua), Browser: name, IsBot: true}
+	}
+
+	browser, version := detectBrowser(ua)
+	return Info{
+		Platform:       detectPlatform(ua),
+		OS:             detectOS(ua),
+		Browser:        browser,
+		BrowserVersion: version,
+		IsBot:          false,
+	}
+}
+
+func detectBot(ua string) (string, bool) {
+	for _, b := range knownBots {
+		if strings.Contains(ua, b.marker) {
+			return b.name, true
+		}
+	}
+	return "", false
+}
+
+func detectBrowser(ua string) (string, string) {
+	for _, b := range browserPatterns {
+		if m := b.pattern.FindStringSubmatch(ua); m != nil {
+			return b.name, m[1]
+		}
+	}
+	return "Unknown", ""
+}
+
+func detectOS(ua string) string {
+	for _, o := range osPatterns {
+		if strings.Contains(ua, o.marker) {
+			return o.name
+		}
+	}
+	return "Unknown"
+}
+
+func detectPlatform(ua string) string {
+	for _, m := range mobileMarkers {
+		if strings.Contains(ua, m) {
+			return "Mobile"
+		}
+	}
+	return "Desktop"
+}
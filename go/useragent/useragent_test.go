@@ -0,0 +1,100 @@
+package useragent
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc     string
+		ua       string
+		expected Info
+	}{
+		{
+			"Googlebot",
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "Googlebot", IsBot: true},
+		},
+		{
+			"Bingbot",
+			"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "Bingbot", IsBot: true},
+		},
+		{
+			"DuckDuckBot",
+			"DuckDuckBot/1.1; (+http://duckduckgo.com/duckduckbot.html)",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "DuckDuckBot", IsBot: true},
+		},
+		{
+			"curl",
+			"curl/8.4.0",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "curl", IsBot: true},
+		},
+		{
+			"wget",
+			"Wget/1.21.3",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "Wget", IsBot: true},
+		},
+		{
+			"python-requests",
+			"python-requests/2.31.0",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "python-requests", IsBot: true},
+		},
+		{
+			"Chrome on Windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Safari/537.36",
+			Info{Platform: "Desktop", OS: "Windows", Browser: "Chrome", BrowserVersion: "116.0.0.0"},
+		},
+		{
+			"Firefox on Linux",
+			"Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/115.0",
+			Info{Platform: "Desktop", OS: "Linux", Browser: "Firefox", BrowserVersion: "115.0"},
+		},
+		{
+			"Safari on macOS",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15",
+			Info{Platform: "Desktop", OS: "macOS", Browser: "Safari", BrowserVersion: "16.5"},
+		},
+		{
+			"Edge on Windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Safari/537.36 Edg/116.0.1938.62",
+			Info{Platform: "Desktop", OS: "Windows", Browser: "Edge", BrowserVersion: "116.0.1938.62"},
+		},
+		{
+			"Safari on iOS",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 16_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Mobile/15E148 Safari/604.1",
+			Info{Platform: "Mobile", OS: "iOS", Browser: "Safari", BrowserVersion: "16.5"},
+		},
+		{
+			"Chrome on Android",
+			"Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Mobile Safari/537.36",
+			Info{Platform: "Mobile", OS: "Android", Browser: "Chrome", BrowserVersion: "116.0.0.0"},
+		},
+		{
+			"CLI override takes priority over bot detection",
+			"URLShortenerCLI/1.0 curl/8.4.0",
+			Info{Platform: "Unknown", OS: "Unknown", Browser: "CLI App"},
+		},
+		{
+			"unknown UA",
+			"SomeRandomThing/1.0",
+			Info{Platform: "Desktop", OS: "Unknown", Browser: "Unknown"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			got := Parse(c.ua)
+			if got != c.expected {
+				t.Errorf("Parse(%q) = %+v, want %+v", c.ua, got, c.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	ua := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/116.0.0.0 Safari/537.36"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Parse(ua)
+	}
+}